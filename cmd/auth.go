@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command group
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect Spotify authentication",
+	Long:  `Inspect the stored Spotify login and diagnose authentication problems.`,
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the stored token, its scopes and the authenticated user",
+	Long: `Shows the token file location, token expiry, granted scopes, whether the
+refresh token still works and which Spotify user the token belongs to.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthStatus,
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	spotifyAuth, err := newSpotifyAuth()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🔐 Authentication status")
+	fmt.Println("========================")
+	fmt.Printf("📁 Token file: %s\n", spotifyAuth.TokenFile())
+
+	status, err := spotifyAuth.Status()
+	if err != nil {
+		fmt.Println("❌ Not logged in (no usable token stored)")
+		fmt.Println("   Run any playlist command, e.g. 'spotify-shuffle interactive', to log in.")
+		return nil
+	}
+
+	fmt.Printf("⏰ Expires: %s\n", describeExpiry(status.Expiry, time.Now()))
+	fmt.Printf("🔑 Scopes: %s\n", strings.Join(status.Scopes, ", "))
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	if !status.HasRefreshToken {
+		fmt.Println("🔄 Refresh: ❌ no refresh token stored; you will need to log in again once the token expires")
+	} else if expiry, err := spotifyAuth.Refresh(ctx); err != nil {
+		fmt.Printf("🔄 Refresh: ❌ failed: %v\n", err)
+		fmt.Println("   Delete the token file and log in again.")
+	} else {
+		fmt.Printf("🔄 Refresh: ✅ works (new token %s)\n", describeExpiry(expiry, time.Now()))
+	}
+
+	client, err := spotifyAuth.StoredClient()
+	if err != nil {
+		return err
+	}
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		fmt.Printf("👤 User: ❌ failed to look up: %v\n", err)
+		return nil
+	}
+	fmt.Printf("👤 User: %s (%s)\n", user.DisplayName, user.ID)
+
+	return nil
+}
+
+// describeExpiry renders a token expiry relative to now
+func describeExpiry(expiry, now time.Time) string {
+	if expiry.IsZero() {
+		return "never"
+	}
+
+	stamp := expiry.Local().Format("2006-01-02 15:04:05")
+	if expiry.Before(now) {
+		return fmt.Sprintf("%s (expired %s ago)", stamp, now.Sub(expiry).Round(time.Second))
+	}
+	return fmt.Sprintf("%s (in %s)", stamp, expiry.Sub(now).Round(time.Second))
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+}
@@ -0,0 +1,367 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/auth"
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	backupOut         string
+	restoreOnly       []string
+	restoreNew        bool
+	restoreNameSuffix string
+	restoreList       bool
+	restoreDryRun     bool
+	restoreYes        bool
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Save all playlists you own to a backup file",
+	Long: `Writes every playlist you own to a versioned JSON archive: name, description,
+public and collaborative flags, and the tracks in order with when and by
+whom they were added. Local files are left out. Files ending in .gz are
+compressed.
+
+Use --match to back up only playlists whose names match a pattern.
+
+Examples:
+  spotify-shuffle backup
+  spotify-shuffle backup --out playlists.json
+  spotify-shuffle backup --match "Team *" --out team.json.gz`,
+	Args: cobra.NoArgs,
+	RunE: runBackup,
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <backup-file>",
+	Short: "Recreate playlists from a backup file",
+	Long: `Restores playlists from a file written by 'backup'. By default every playlist
+in the backup is restored; --only picks playlists by name or ID.
+
+A playlist that is still in your library is overwritten with the backed up
+name, description, visibility and tracks. One that is gone is created
+again. With --new, every playlist is restored as a new copy instead and the
+originals are left alone.
+
+Spotify dates restored tracks as added now. Local files are not part of
+backups, since the API can't add them to a playlist.
+
+Examples:
+  spotify-shuffle restore spotify-backup-20240501-120000.json.gz --list
+  spotify-shuffle restore backup.json --only "Road Trip" --new
+  spotify-shuffle restore backup.json --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if len(playlistIDs) > 0 || playlistsFile != "" {
+		return fmt.Errorf("'backup' saves the playlists you own; use --match to select some of them by name")
+	}
+
+	out := backupOut
+	if out == "" {
+		out = fmt.Sprintf("spotify-backup-%s.json.gz", time.Now().Format("20060102-150405"))
+	}
+	if out == "-" {
+		// Keep stdout clean for the backup itself
+		statusOut = os.Stderr
+	}
+
+	client, err := getAuthenticatedClient(ctx, auth.ScopesPlaylistRead...)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	manager, done := newManager(client)
+	defer done()
+
+	playlists, err := manager.SelectPlaylists(ctx, true, matchPattern)
+	if err != nil {
+		return err
+	}
+	if len(playlists) == 0 {
+		return fmt.Errorf("no owned playlists to back up")
+	}
+
+	backup := &playlist.Backup{
+		Version: playlist.BackupVersion,
+		Created: time.Now().UTC(),
+		User:    playlists[0].Owner.ID,
+	}
+	tracks := 0
+	for i, p := range playlists {
+		fmt.Fprintf(statusOut, "💾 [%d/%d] %s\n", i+1, len(playlists), p.Name)
+		pb, err := manager.BackupPlaylist(ctx, p)
+		if err != nil {
+			return fmt.Errorf("failed to back up '%s': %w", p.Name, err)
+		}
+		backup.Playlists = append(backup.Playlists, pb)
+		tracks += len(pb.Tracks)
+	}
+
+	if err := saveBackup(out, backup); err != nil {
+		return err
+	}
+	if out != "-" {
+		fmt.Fprintf(statusOut, "✅ Backed up %d playlists (%d tracks) to %s\n", len(backup.Playlists), tracks, out)
+	}
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if batchRequested() || len(playlistIDs) > 0 {
+		return fmt.Errorf("'restore' takes playlists from the backup file; use --only to select them")
+	}
+
+	backup, err := loadBackup(args[0])
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectBackupPlaylists(backup, restoreOnly)
+	if err != nil {
+		return err
+	}
+
+	if restoreList {
+		printBackupContents(os.Stdout, backup, selected)
+		return nil
+	}
+
+	client, err := getAuthenticatedClient(ctx, auth.ScopesPlaylistModify...)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	manager, done := newManager(client)
+	defer done()
+
+	// Playlists still in the library are overwritten unless --new is given
+	owned := make(map[string]bool)
+	if !restoreNew {
+		playlists, err := manager.SelectPlaylists(ctx, true, "")
+		if err != nil {
+			return err
+		}
+		for _, p := range playlists {
+			owned[string(p.ID)] = true
+		}
+	}
+
+	var overwrite []string
+	for _, p := range selected {
+		if owned[p.ID] {
+			overwrite = append(overwrite, p.Name)
+		}
+	}
+
+	if restoreDryRun {
+		for _, p := range selected {
+			action := "create"
+			if owned[p.ID] {
+				action = "overwrite"
+			}
+			fmt.Printf("🔍 Would %s '%s' with %d tracks\n", action, restoreName(p, owned[p.ID]), len(p.Tracks))
+		}
+		return nil
+	}
+
+	if len(overwrite) > 0 && !restoreYes {
+		fmt.Printf("📋 Will overwrite: %s\n", strings.Join(overwrite, ", "))
+		if !confirmAction(fmt.Sprintf("replace the current contents of %d playlists", len(overwrite))) {
+			fmt.Println("❌ Operation cancelled")
+			return nil
+		}
+	}
+
+	failed := 0
+	for i, p := range selected {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		target := spotify.ID("")
+		if owned[p.ID] {
+			target = spotify.ID(p.ID)
+		}
+		restored := p
+		restored.Name = restoreName(p, owned[p.ID])
+
+		fmt.Fprintf(statusOut, "♻️  [%d/%d] %s\n", i+1, len(selected), restored.Name)
+		result, err := manager.RestorePlaylist(ctx, restored, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to restore '%s': %v\n", p.Name, err)
+			failed++
+			continue
+		}
+		reportRestore(result)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d playlists failed to restore", failed, len(selected))
+	}
+	fmt.Fprintf(statusOut, "✅ Restored %d playlists\n", len(selected))
+	return nil
+}
+
+// restoreName returns the name a playlist is restored under: its own when
+// overwriting or recreating it, with the suffix for copies made by --new
+func restoreName(p playlist.PlaylistBackup, overwriting bool) string {
+	if restoreNew && !overwriting {
+		return p.Name + restoreNameSuffix
+	}
+	return p.Name
+}
+
+func reportRestore(result playlist.RestoreResult) {
+	verb := "Overwrote"
+	if result.Created {
+		verb = "Created"
+	}
+	fmt.Fprintf(statusOut, "   %s %s with %d tracks\n", verb, result.PlaylistID, result.Tracks)
+}
+
+// selectBackupPlaylists returns the playlists named by refs, or all of them
+// when there are none. Every ref must match.
+func selectBackupPlaylists(backup *playlist.Backup, refs []string) ([]playlist.PlaylistBackup, error) {
+	if len(refs) == 0 {
+		return backup.Playlists, nil
+	}
+
+	var selected []playlist.PlaylistBackup
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		found := backup.Find(ref)
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no playlist named or with ID %q in the backup", ref)
+		}
+		for _, p := range found {
+			if !seen[p.ID] {
+				seen[p.ID] = true
+				selected = append(selected, p)
+			}
+		}
+	}
+	return selected, nil
+}
+
+// printBackupContents describes a backup and lists the selected playlists
+func printBackupContents(w io.Writer, backup *playlist.Backup, selected []playlist.PlaylistBackup) {
+	fmt.Fprintf(w, "📦 Backup from %s (format version %d)\n", backup.Created.Local().Format("2006-01-02 15:04"), backup.Version)
+	if backup.User != "" {
+		fmt.Fprintf(w, "👤 User: %s\n", backup.User)
+	}
+	fmt.Fprintln(w)
+
+	for i, p := range selected {
+		var flags []string
+		if p.Public {
+			flags = append(flags, "public")
+		} else {
+			flags = append(flags, "private")
+		}
+		if p.Collaborative {
+			flags = append(flags, "collaborative")
+		}
+		fmt.Fprintf(w, "%3d. %s (%d tracks, %s)\n     %s\n", i+1, p.Name, len(p.Tracks), strings.Join(flags, ", "), p.ID)
+	}
+}
+
+// saveBackup writes the backup to path, compressed when the name ends in
+// .gz, or to stdout for "-". The file is replaced only once it's complete.
+func saveBackup(path string, backup *playlist.Backup) error {
+	var buf bytes.Buffer
+	if strings.HasSuffix(path, ".gz") {
+		zw := gzip.NewWriter(&buf)
+		if err := playlist.WriteBackup(zw, backup); err != nil {
+			return fmt.Errorf("failed to encode backup: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to compress backup: %w", err)
+		}
+	} else if err := playlist.WriteBackup(&buf, backup); err != nil {
+		return fmt.Errorf("failed to encode backup: %w", err)
+	}
+
+	if path == "-" {
+		_, err := buf.WriteTo(os.Stdout)
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".spotify-backup-*")
+	if err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := buf.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	return nil
+}
+
+// loadBackup reads a backup file, decompressing it if it's gzipped
+func loadBackup(path string) (*playlist.Backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	var magic [2]byte
+	if n, _ := io.ReadFull(f, magic[:]); n == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to read backup: %w", err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress backup: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	} else if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	backup, err := playlist.ReadBackup(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return backup, nil
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	backupCmd.Flags().StringVarP(&backupOut, "out", "o", "", "Backup file (default spotify-backup-<time>.json.gz, '-' for stdout)")
+
+	restoreCmd.Flags().StringArrayVar(&restoreOnly, "only", nil, "Restore only this playlist, by name or ID (repeatable)")
+	restoreCmd.Flags().BoolVar(&restoreNew, "new", false, "Restore into new playlists instead of overwriting existing ones")
+	restoreCmd.Flags().StringVar(&restoreNameSuffix, "name-suffix", " (restored)", "Suffix for playlists restored as new copies with --new")
+	restoreCmd.Flags().BoolVar(&restoreList, "list", false, "List the playlists in the backup without restoring")
+	restoreCmd.Flags().BoolVar(&restoreDryRun, "dry-run", false, "Show what would be restored without changing anything")
+	restoreCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "Overwrite existing playlists without asking")
+}
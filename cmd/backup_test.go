@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+func TestSaveAndLoadBackup(t *testing.T) {
+	backup := &playlist.Backup{
+		Version:   playlist.BackupVersion,
+		Playlists: []playlist.PlaylistBackup{{ID: "p1", Name: "Road Trip", Tracks: []playlist.BackupTrack{{URI: "spotify:track:1"}}}},
+	}
+
+	for _, name := range []string{"backup.json", "backup.json.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := saveBackup(path, backup); err != nil {
+				t.Fatalf("saveBackup() error = %v", err)
+			}
+			got, err := loadBackup(path)
+			if err != nil {
+				t.Fatalf("loadBackup() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Playlists, backup.Playlists) {
+				t.Errorf("loaded %+v, want %+v", got.Playlists, backup.Playlists)
+			}
+		})
+	}
+}
+
+func TestSelectBackupPlaylists(t *testing.T) {
+	backup := &playlist.Backup{Playlists: []playlist.PlaylistBackup{
+		{ID: "p1", Name: "Road Trip"},
+		{ID: "p2", Name: "Focus"},
+	}}
+
+	all, err := selectBackupPlaylists(backup, nil)
+	if err != nil || len(all) != 2 {
+		t.Errorf("no selection = %v, %v; want all playlists", all, err)
+	}
+
+	some, err := selectBackupPlaylists(backup, []string{"focus", "p2"})
+	if err != nil || len(some) != 1 || some[0].ID != "p2" {
+		t.Errorf("selection = %v, %v; want only p2 once", some, err)
+	}
+
+	if _, err := selectBackupPlaylists(backup, []string{"Missing"}); err == nil {
+		t.Error("unknown playlist should be an error")
+	}
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/zmb3/spotify/v2"
+)
+
+// playlistTarget is one playlist selected for a batch run
+type playlistTarget struct {
+	ID   spotify.ID
+	Name string
+}
+
+// batchResult records the outcome of running a command on one playlist
+type batchResult struct {
+	target playlistTarget
+	err    error
+}
+
+// batchRequested reports whether the command line selects more than one
+// playlist, or selects playlists by file, ownership or name
+func batchRequested() bool {
+	return len(playlistIDs) > 1 || playlistsFile != "" || allOwned || matchPattern != ""
+}
+
+// requireSinglePlaylist rejects batch selections for commands that only
+// make sense on one playlist
+func requireSinglePlaylist(command string) error {
+	if batchRequested() {
+		return fmt.Errorf("'%s' works on a single playlist; batch options (repeated --playlist, --playlists-file, --all-owned, --match) are not supported", command)
+	}
+	return nil
+}
+
+// runBatch runs fn on every selected playlist, continuing past failures,
+// and prints a summary table at the end
+func runBatch(ctx context.Context, client *spotify.Client, fn PlaylistCommandFunc) error {
+	targets, err := resolvePlaylistTargets(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no playlists matched the batch selection")
+	}
+
+	fmt.Fprintf(statusOut, "📚 Running on %d playlists\n", len(targets))
+
+	var results []batchResult
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			results = append(results, batchResult{target: target, err: fmt.Errorf("skipped: %w", ctx.Err())})
+			continue
+		}
+
+		name, err := runOnPlaylist(ctx, client, target.ID, fn)
+		if name != "" {
+			target.Name = name
+		}
+		if err != nil {
+			fmt.Fprintf(statusOut, "❌ %v\n", err)
+		}
+		results = append(results, batchResult{target: target, err: err})
+	}
+
+	failed := printBatchSummary(statusOut, results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d playlists failed", failed, len(results))
+	}
+	return nil
+}
+
+// resolvePlaylistTargets collects the playlists selected by --playlist,
+// --playlists-file, --all-owned and --match, without duplicates. A name
+// reference that matches no playlist, or several, fails the whole run.
+func resolvePlaylistTargets(ctx context.Context, client *spotify.Client) ([]playlistTarget, error) {
+	inputs := append([]string(nil), playlistIDs...)
+
+	if playlistsFile != "" {
+		f, err := os.Open(playlistsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read playlists file: %w", err)
+		}
+		defer f.Close()
+
+		fromFile, err := readPlaylistList(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read playlists file: %w", err)
+		}
+		inputs = append(inputs, fromFile...)
+	}
+
+	var targets []playlistTarget
+	seen := make(map[spotify.ID]bool)
+	add := func(t playlistTarget) {
+		if t.ID != "" && !seen[t.ID] {
+			seen[t.ID] = true
+			targets = append(targets, t)
+		}
+	}
+
+	for _, input := range inputs {
+		id, err := resolvePlaylistRef(ctx, client, input)
+		if err != nil {
+			return nil, err
+		}
+		add(playlistTarget{ID: id})
+	}
+
+	if allOwned || matchPattern != "" {
+		playlists, err := playlist.NewManager(client).SelectPlaylists(ctx, allOwned, matchPattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range playlists {
+			add(playlistTarget{ID: p.ID, Name: p.Name})
+		}
+	}
+
+	return targets, nil
+}
+
+// readPlaylistList reads playlist IDs or URLs, one per line. Blank lines
+// and lines starting with '#' are ignored.
+func readPlaylistList(r io.Reader) ([]string, error) {
+	var inputs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		inputs = append(inputs, line)
+	}
+	return inputs, scanner.Err()
+}
+
+// printBatchSummary prints one row per playlist and returns the number of
+// failures
+func printBatchSummary(w io.Writer, results []batchResult) int {
+	width := len("PLAYLIST")
+	for _, r := range results {
+		if n := utf8.RuneCountInString(batchTargetName(r.target)); n > width {
+			width = n
+		}
+	}
+
+	fmt.Fprintf(w, "\n📋 Summary\n")
+	fmt.Fprintf(w, "%-*s  %s\n", width, "PLAYLIST", "RESULT")
+
+	failed := 0
+	for _, r := range results {
+		status := "✅ ok"
+		if r.err != nil {
+			status = "❌ " + r.err.Error()
+			failed++
+		}
+		fmt.Fprintf(w, "%-*s  %s\n", width, batchTargetName(r.target), status)
+	}
+
+	fmt.Fprintf(w, "\n✅ %d succeeded, ❌ %d failed\n", len(results)-failed, failed)
+	return failed
+}
+
+func batchTargetName(t playlistTarget) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return string(t.ID)
+}
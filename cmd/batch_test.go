@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadPlaylistList(t *testing.T) {
+	input := `# Team playlists
+37i9dQZF1DXcBWIGoYBM5M
+
+  https://open.spotify.com/playlist/5ABHKGoOzxkaa28ttQV9sE?si=abc
+# spotify:playlist:ignored
+spotify:playlist:1A2B3C
+`
+	got, err := readPlaylistList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readPlaylistList() error = %v", err)
+	}
+
+	want := []string{
+		"37i9dQZF1DXcBWIGoYBM5M",
+		"https://open.spotify.com/playlist/5ABHKGoOzxkaa28ttQV9sE?si=abc",
+		"spotify:playlist:1A2B3C",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readPlaylistList() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintBatchSummary(t *testing.T) {
+	var buf bytes.Buffer
+	failed := printBatchSummary(&buf, []batchResult{
+		{target: playlistTarget{ID: "a", Name: "Team Alpha"}},
+		{target: playlistTarget{ID: "b"}, err: errors.New("failed to access playlist")},
+	})
+
+	if failed != 1 {
+		t.Errorf("printBatchSummary() = %d failures, want 1", failed)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Team Alpha  ✅ ok", "b           ❌ failed to access playlist", "1 succeeded, ❌ 1 failed"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBatchRequested(t *testing.T) {
+	defer func() {
+		playlistIDs, playlistsFile, allOwned, matchPattern = nil, "", false, ""
+	}()
+
+	playlistIDs = []string{"one"}
+	if batchRequested() {
+		t.Error("batchRequested() = true for a single --playlist")
+	}
+
+	playlistIDs = []string{"one", "two"}
+	if !batchRequested() {
+		t.Error("batchRequested() = false for repeated --playlist")
+	}
+
+	playlistIDs = nil
+	matchPattern = "Team *"
+	if !batchRequested() {
+		t.Error("batchRequested() = false for --match")
+	}
+	if err := requireSinglePlaylist("export"); err == nil {
+		t.Error("requireSinglePlaylist() expected error in batch mode")
+	}
+}
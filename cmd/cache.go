@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command group
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clear the local metadata cache",
+	Long: `Track, album, artist and genre metadata and playlist contents are cached on disk
+so repeated commands don't have to fetch them from Spotify again. Use --no-cache on any command to bypass it.
+
+Examples:
+  spotify-shuffle cache stats
+  spotify-shuffle cache clear`,
+}
+
+// cacheStatsCmd represents the cache stats command
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show what the cache holds",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStats,
+}
+
+// cacheClearCmd represents the cache clear command
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete all cached metadata",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClear,
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	c, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📁 Cache directory: %s\n\n", c.Dir())
+
+	stats, err := c.Stats()
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %w", err)
+	}
+	if len(stats) == 0 {
+		fmt.Println("ℹ️  Cache is empty")
+		return nil
+	}
+
+	fmt.Printf("%-10s %8s %10s  %-16s  %-16s\n", "BUCKET", "ENTRIES", "SIZE", "OLDEST", "NEWEST")
+	var entries int
+	var bytes int64
+	for _, s := range stats {
+		fmt.Printf("%-10s %8d %10s  %-16s  %-16s\n", s.Name, s.Entries, formatBytes(s.Bytes), formatCacheTime(s.Oldest), formatCacheTime(s.Newest))
+		entries += s.Entries
+		bytes += s.Bytes
+	}
+	fmt.Printf("\n📊 Total: %d entries, %s\n", entries, formatBytes(bytes))
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	c, err := openCache()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Clear(); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+
+	fmt.Println("✅ Cache cleared")
+	return nil
+}
+
+// openCache opens the cache in its default location
+func openCache() (*cache.Cache, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return cache.Open(dir)
+}
+
+// attachCache opens the cache for a command unless --no-cache was given.
+// The returned function writes cached data back to disk; a cache that
+// can't be opened only disables caching.
+func attachCache() (*cache.Cache, func()) {
+	if noCache {
+		return nil, func() {}
+	}
+
+	c, err := openCache()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Metadata cache disabled: %v\n", err)
+		return nil, func() {}
+	}
+
+	return c, func() {
+		if err := c.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to save metadata cache: %v\n", err)
+		}
+	}
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func formatCacheTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
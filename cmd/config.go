@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/petabloc/spotify-shuffle/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var showSecrets bool
+
+// configCmd represents the config command group
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and manage configuration",
+	Long: `Inspect and manage the Spotify Shuffle configuration file without the interactive wizard.
+
+Examples:
+  spotify-shuffle config show
+  spotify-shuffle config get defaults.chunk_size
+  spotify-shuffle config set spotify.client_id abc123
+  spotify-shuffle config edit`,
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a config key",
+	Long:  `Prints the effective value of a config key, taking the config file, environment variables and defaults into account.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config key in the config file",
+	Long:  `Validates the value against the configuration schema and writes it to the config file, preserving other keys and comments.`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+// configShowCmd represents the config show command
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long:  `Shows every known config key with its effective value. Secrets are redacted unless --show-secrets is given.`,
+	Args:  cobra.NoArgs,
+	RunE:  runConfigShow,
+}
+
+// configPathCmd represents the config path command
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the config file location",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(config.ConfigPath())
+		return nil
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Long:  `Opens the config file in $VISUAL or $EDITOR and validates it once the editor exits.`,
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEdit,
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:          "validate",
+	Short:        "Check the config file for unknown keys and bad values",
+	Long:         `Validates the config file against the configuration schema and reports unknown keys and invalid values.`,
+	Args:         cobra.NoArgs,
+	RunE:         runConfigValidate,
+	SilenceUsage: true,
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, err := config.Value(args[0])
+	if err != nil {
+		return fmt.Errorf("%w (run 'spotify-shuffle config show' to list keys)", err)
+	}
+
+	if value != nil {
+		fmt.Println(value)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	if err := config.Set(key, value); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	display := value
+	if f, _ := config.LookupField(key); f.Secret {
+		display = config.Redact(value)
+	}
+	fmt.Printf("✅ %s = %s\n", key, display)
+	return nil
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	fmt.Printf("📁 Config file: %s\n\n", config.ConfigPath())
+
+	width := 0
+	for _, f := range config.Fields() {
+		if len(f.Key) > width {
+			width = len(f.Key)
+		}
+	}
+
+	for _, f := range config.Fields() {
+		value, err := config.Value(f.Key)
+		if err != nil {
+			return err
+		}
+
+		display := ""
+		if value != nil {
+			display = fmt.Sprint(value)
+		}
+		if f.Secret && !showSecrets {
+			display = config.Redact(display)
+		}
+		fmt.Printf("%-*s  %s\n", width, f.Key, display)
+	}
+
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	if err := config.EnsureFile(); err != nil {
+		return fmt.Errorf("failed to create config file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// $EDITOR may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	editCmd := exec.Command(parts[0], append(parts[1:], config.ConfigPath())...)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+
+	if err := editCmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %q: %w", editor, err)
+	}
+
+	return runConfigValidate(cmd, nil)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	problems, err := config.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate config: %w", err)
+	}
+
+	fmt.Printf("📁 Config file: %s\n", config.ConfigPath())
+
+	if len(problems) == 0 {
+		fmt.Println("✅ Configuration is valid")
+		return nil
+	}
+
+	fmt.Printf("\n❌ Found %d problem(s):\n", len(problems))
+	for _, p := range problems {
+		fmt.Printf("   • %s\n", p)
+	}
+
+	return fmt.Errorf("configuration is invalid")
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configShowCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret values instead of redacting them")
+}
@@ -8,8 +8,11 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/petabloc/spotify-shuffle/internal/config"
 	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/progress"
 	"github.com/spf13/cobra"
 	"github.com/zmb3/spotify/v2"
 )
@@ -19,21 +22,78 @@ var (
 	name        string
 	days        int
 	chunkSize   int
-	genre       string
+	genres      []string
 	overwrite   bool
 	interactive bool
+
+	chunkStrategy     string
+	chunkNameTemplate string
+	minutes           int
+	durationSelect    string
+	durationTolerance int
+	freshBy           string
+	freshSince        string
+	freshUntil        string
+	excludeGenres     []string
+	genreMatch        string
+	groupGenres       bool
+	createWhere       string
 )
 
 // createCmd represents the create command
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create new playlists from existing playlist",
-	Long:  `Create new playlists from an existing playlist using various methods: fresh (recent tracks), chunk (split into smaller playlists), or genre-based filtering.`,
-	RunE:  runCreate,
+	Long: `Create new playlists from an existing playlist using various methods: fresh (recent tracks), chunk (split into smaller playlists), genre-based filtering, duration (a set lasting --minutes), or filter (tracks matching --where).
+
+Chunk strategies:
+  random    shuffle, then split into chunks of --size tracks (default)
+  order     split in the current playlist order
+  added     split in the order tracks were added
+  genre     one or more chunks per genre
+  artist    chunks of up to --size tracks, never splitting an artist
+  duration  chunks of up to --minutes of playing time, in playlist order
+
+Chunk names come from --name-template, e.g. "{base} {n} of {total}" or
+"{base} {n} - {label}" where {label} is the chunk's genre, artists, months
+or length. Names must differ, and labels repeat when a genre fills several
+chunks, so keep {index} or {n} in the template. Each chunk's description
+records the source playlist and date.
+
+Genre playlists keep tracks with any --genre and none of the
+--exclude-genre values. Genres match as substrings by default
+(--genre-match contains, so "rock" also finds "indie rock"); use
+--genre-match exact or --genre-match regex to be stricter.
+--group-genres also matches broad parent genres, so --genre metal
+--genre-match exact --group-genres finds every metal micro-genre.
+
+Fresh playlists keep the tracks added in the last --days days, or
+released in them with --by released. --since and --until give a fixed
+range of days instead, e.g. --since 2026-01-01 --until 2026-03-31.
+
+Filter playlists keep the tracks matching a --where expression, in the
+same language as 'remove --where', e.g. --where 'year < 1990 and popularity > 60'.
+
+Duration playlists pick tracks until their total length is within
+--tolerance minutes of --minutes, taking them at random (default), most
+popular first (--select popular) or in title, artist or added order.`,
+	RunE: runCreate,
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
-	return runPlaylistCommand(func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	if err := requireSinglePlaylist("create"); err != nil {
+		return err
+	}
+
+	defaults := config.GetDefaults()
+	if !cmd.Flags().Changed("days") && defaults.FreshDays > 0 {
+		days = defaults.FreshDays
+	}
+	if !cmd.Flags().Changed("size") && defaults.ChunkSize > 0 {
+		chunkSize = defaults.ChunkSize
+	}
+
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
 		switch createType {
 		case "fresh":
 			return createFreshPlaylist(ctx, manager, playlistID)
@@ -41,15 +101,20 @@ func runCreate(cmd *cobra.Command, args []string) error {
 			return createChunkPlaylists(ctx, manager, playlistID)
 		case "genre":
 			return createGenrePlaylist(ctx, manager, playlistID)
+		case "duration":
+			return createDurationPlaylist(ctx, manager, playlistID)
+		case "filter":
+			return createFilteredPlaylist(ctx, manager, playlistID)
 		default:
-			return fmt.Errorf("invalid create type: %s (use 'fresh', 'chunk', or 'genre')", createType)
+			return fmt.Errorf("invalid create type: %s (use 'fresh', 'chunk', 'genre', 'duration' or 'filter')", createType)
 		}
 	})
 }
 
 func createFreshPlaylist(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
-	if days <= 0 {
-		return fmt.Errorf("days must be greater than 0")
+	opts, err := freshOptions()
+	if err != nil {
+		return err
 	}
 
 	if name == "" {
@@ -79,9 +144,9 @@ func createFreshPlaylist(ctx context.Context, manager *playlist.Manager, playlis
 		}
 	}
 
-	fmt.Printf("🔍 Creating fresh playlist with tracks from last %d days...\n", days)
+	fmt.Printf("🔍 Creating fresh playlist with tracks %s...\n", opts.Describe())
 
-	trackCount, err := manager.CreateFreshPlaylist(ctx, playlistID, name, days, overwrite)
+	trackCount, err := manager.CreateFreshPlaylist(ctx, playlistID, name, opts, overwrite)
 	if err != nil {
 		return fmt.Errorf("failed to create fresh playlist: %w", err)
 	}
@@ -90,11 +155,42 @@ func createFreshPlaylist(ctx context.Context, manager *playlist.Manager, playlis
 	return nil
 }
 
+// freshOptions builds the fresh playlist options from the flags; --since
+// and --until take precedence over --days
+func freshOptions() (playlist.FreshOptions, error) {
+	opts := playlist.FreshOptions{By: freshBy, Days: days}
+	for _, d := range []struct {
+		flag  string
+		value string
+		into  *time.Time
+	}{{"since", freshSince, &opts.Since}, {"until", freshUntil, &opts.Until}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.ParseInLocation("2006-01-02", d.value, time.Local)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --%s date %q (use YYYY-MM-DD)", d.flag, d.value)
+		}
+		*d.into = t
+	}
+	return opts, opts.Check()
+}
+
 func createChunkPlaylists(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
 	if chunkSize <= 0 {
 		chunkSize = 250 // Default chunk size
 	}
 
+	opts := playlist.ChunkOptions{
+		Strategy:     chunkStrategy,
+		Size:         chunkSize,
+		Duration:     time.Duration(minutes) * time.Minute,
+		NameTemplate: chunkNameTemplate,
+	}
+	if err := opts.Check(); err != nil {
+		return err
+	}
+
 	if name == "" {
 		if interactive {
 			reader := bufio.NewReader(os.Stdin)
@@ -109,11 +205,14 @@ func createChunkPlaylists(ctx context.Context, manager *playlist.Manager, playli
 
 	// Check for existing chunk playlists if not overwriting
 	if !overwrite && interactive {
-		// Check for existing chunks
+		chunks, err := manager.PlanChunkPlaylists(ctx, playlistID, name, opts)
+		if err != nil {
+			return fmt.Errorf("failed to plan chunk playlists: %w", err)
+		}
+
 		existingCount := 0
-		for i := 0; i < 100; i++ { // Check first 100 possible chunks
-			chunkName := fmt.Sprintf("%s-%02d", name, i)
-			if _, err := manager.FindPlaylistByName(ctx, chunkName); err == nil {
+		for _, chunk := range chunks {
+			if _, err := manager.FindPlaylistByName(ctx, chunk.Name); err == nil {
 				existingCount++
 			}
 		}
@@ -131,9 +230,13 @@ func createChunkPlaylists(ctx context.Context, manager *playlist.Manager, playli
 		}
 	}
 
-	fmt.Printf("🔍 Creating chunk playlists with %d tracks per chunk...\n", chunkSize)
+	if opts.Strategy == "duration" {
+		fmt.Printf("🔍 Creating chunk playlists of up to %d minutes each...\n", minutes)
+	} else {
+		fmt.Printf("🔍 Creating chunk playlists with up to %d tracks per chunk...\n", chunkSize)
+	}
 
-	createdCount, err := manager.CreateChunkPlaylists(ctx, playlistID, name, chunkSize, overwrite)
+	createdCount, err := manager.CreateChunkPlaylists(ctx, playlistID, name, opts, overwrite)
 	if err != nil {
 		return fmt.Errorf("failed to create chunk playlists: %w", err)
 	}
@@ -142,15 +245,84 @@ func createChunkPlaylists(ctx context.Context, manager *playlist.Manager, playli
 	return nil
 }
 
+func createDurationPlaylist(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	if minutes <= 0 {
+		return fmt.Errorf("minutes must be greater than 0")
+	}
+	if durationTolerance < 0 {
+		return fmt.Errorf("tolerance must not be negative")
+	}
+	if name == "" {
+		return fmt.Errorf("playlist name is required (use --name)")
+	}
+
+	if !overwrite {
+		if _, err := manager.FindPlaylistByName(ctx, name); err == nil {
+			return fmt.Errorf("playlist '%s' already exists (use --overwrite to replace)", name)
+		}
+	}
+
+	target := time.Duration(minutes) * time.Minute
+	tolerance := time.Duration(durationTolerance) * time.Minute
+	fmt.Printf("🔍 Picking %s tracks for a %d-minute playlist (±%d min)...\n", durationSelect, minutes, durationTolerance)
+
+	selection, err := manager.CreateDurationPlaylist(ctx, playlistID, name, target, tolerance, durationSelect, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to create duration playlist: %w", err)
+	}
+
+	if !selection.Within {
+		fmt.Printf("⚠️  Could not get within %d minutes of the target with these tracks\n", durationTolerance)
+	}
+	fmt.Printf("✅ Created playlist '%s' with %d tracks lasting %s!\n", name, len(selection.Tracks), progress.FormatDuration(selection.Total))
+	return nil
+}
+
+func createFilteredPlaylist(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	if createWhere == "" {
+		return fmt.Errorf("a filter expression is required (use --where)")
+	}
+	expr, err := playlist.ParseExpr(createWhere)
+	if err != nil {
+		return fmt.Errorf("invalid --where expression: %w", err)
+	}
+	if name == "" {
+		return fmt.Errorf("playlist name is required (use --name)")
+	}
+
+	if !overwrite {
+		if _, err := manager.FindPlaylistByName(ctx, name); err == nil {
+			return fmt.Errorf("playlist '%s' already exists (use --overwrite to replace)", name)
+		}
+	}
+
+	fmt.Printf("🔍 Creating playlist with tracks where %s...\n", expr)
+
+	trackCount, err := manager.CreateFilteredPlaylist(ctx, playlistID, name, expr, overwrite)
+	if err != nil {
+		return fmt.Errorf("failed to create filtered playlist: %w", err)
+	}
+
+	fmt.Printf("✅ Created playlist '%s' with %d tracks!\n", name, trackCount)
+	return nil
+}
+
 func createGenrePlaylist(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
-	var selectedGenre string
+	filter := playlist.GenreFilter{
+		Include:  genres,
+		Exclude:  excludeGenres,
+		Match:    genreMatch,
+		Taxonomy: groupGenres,
+	}
 
-	if genre != "" {
-		selectedGenre = genre
+	if len(genres) > 0 || len(excludeGenres) > 0 {
+		if err := filter.Check(); err != nil {
+			return err
+		}
 	} else if interactive {
 		// Show available genres
 		fmt.Println("🎵 Getting genres from playlist...")
-		genres, err := manager.GetPlaylistGenres(ctx, playlistID)
+		genres, err := manager.GetPlaylistGenres(ctx, playlistID, groupGenres)
 		if err != nil {
 			return fmt.Errorf("failed to get genres: %w", err)
 		}
@@ -193,15 +365,20 @@ func createGenrePlaylist(ctx context.Context, manager *playlist.Manager, playlis
 			return nil
 		}
 
-		// Check if input is a number
+		// Check if input is a number; a genre picked from the list is
+		// matched exactly so "rock" doesn't also take "rockabilly"
 		if num, err := strconv.Atoi(input); err == nil {
 			if num >= 1 && num <= len(sortedGenres) && num <= maxShow {
-				selectedGenre = sortedGenres[num-1].name
+				filter.Include = []string{sortedGenres[num-1].name}
+				filter.Match = "exact"
 			} else {
 				return fmt.Errorf("invalid genre number: %d", num)
 			}
 		} else {
-			selectedGenre = input
+			filter.Include = []string{input}
+		}
+		if err := filter.Check(); err != nil {
+			return err
 		}
 	} else {
 		return fmt.Errorf("genre is required (use --genre or --interactive)")
@@ -210,7 +387,7 @@ func createGenrePlaylist(ctx context.Context, manager *playlist.Manager, playlis
 	if name == "" {
 		if interactive {
 			reader := bufio.NewReader(os.Stdin)
-			fmt.Printf("Enter name for the '%s' playlist: ", selectedGenre)
+			fmt.Printf("Enter name for the '%s' playlist: ", strings.Join(filter.Include, ", "))
 			input, _ := reader.ReadString('\n')
 			name = strings.TrimSpace(input)
 		}
@@ -238,9 +415,9 @@ func createGenrePlaylist(ctx context.Context, manager *playlist.Manager, playlis
 		}
 	}
 
-	fmt.Printf("🔍 Creating genre playlist for '%s'...\n", selectedGenre)
+	fmt.Printf("🔍 Creating genre playlist for %s...\n", filter.Describe())
 
-	trackCount, err := manager.CreateGenrePlaylist(ctx, playlistID, name, selectedGenre, overwrite)
+	trackCount, err := manager.CreateGenrePlaylist(ctx, playlistID, name, filter, overwrite)
 	if err != nil {
 		return fmt.Errorf("failed to create genre playlist: %w", err)
 	}
@@ -252,11 +429,23 @@ func createGenrePlaylist(ctx context.Context, manager *playlist.Manager, playlis
 func init() {
 	rootCmd.AddCommand(createCmd)
 
-	createCmd.Flags().StringVar(&createType, "type", "", "Creation type: 'fresh', 'chunk', or 'genre' (required)")
+	createCmd.Flags().StringVar(&createType, "type", "", "Creation type: 'fresh', 'chunk', 'genre', 'duration' or 'filter' (required)")
 	createCmd.Flags().StringVar(&name, "name", "", "Playlist name (or base name for chunks)")
 	createCmd.Flags().IntVar(&days, "days", 30, "Number of days for fresh playlist (default: 30)")
+	createCmd.Flags().StringVar(&freshBy, "by", "added", "Date a fresh playlist picks tracks by: "+strings.Join(playlist.FreshDates, ", "))
+	createCmd.Flags().StringVar(&freshSince, "since", "", "Fresh playlist: first day to include (YYYY-MM-DD), instead of --days")
+	createCmd.Flags().StringVar(&freshUntil, "until", "", "Fresh playlist: last day to include (YYYY-MM-DD), instead of --days")
 	createCmd.Flags().IntVar(&chunkSize, "size", 250, "Tracks per chunk for chunk playlists (default: 250)")
-	createCmd.Flags().StringVar(&genre, "genre", "", "Genre name for genre playlist")
+	createCmd.Flags().StringVar(&chunkStrategy, "strategy", "random", "How to split chunk playlists: "+strings.Join(playlist.ChunkStrategies, ", "))
+	createCmd.Flags().IntVar(&minutes, "minutes", 60, "Length of a duration playlist, or of each chunk for the duration strategy")
+	createCmd.Flags().StringVar(&durationSelect, "select", "random", "How to pick tracks for a duration playlist: "+strings.Join(playlist.DurationSelections, ", "))
+	createCmd.Flags().IntVar(&durationTolerance, "tolerance", 2, "How many minutes a duration playlist may differ from --minutes")
+	createCmd.Flags().StringVar(&chunkNameTemplate, "name-template", playlist.DefaultChunkNameTemplate, "Chunk playlist names; placeholders {base}, {index}, {n}, {total}, {label}, {date}")
+	createCmd.Flags().StringSliceVar(&genres, "genre", nil, "Genre for genre playlist (repeatable or comma-separated)")
+	createCmd.Flags().StringSliceVar(&excludeGenres, "exclude-genre", nil, "Leave out tracks with this genre (repeatable or comma-separated)")
+	createCmd.Flags().StringVar(&genreMatch, "genre-match", "contains", "How genres match: "+strings.Join(playlist.GenreMatchModes, ", "))
+	createCmd.Flags().BoolVar(&groupGenres, "group-genres", false, "Also match parent genres such as Metal or Hip Hop from the bundled genre taxonomy")
+	createCmd.Flags().StringVar(&createWhere, "where", "", "Filter expression for filter playlists (see 'remove --help')")
 	createCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing playlists")
 	createCmd.Flags().BoolVar(&interactive, "interactive", false, "Use interactive mode for prompts")
 
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/auth"
+	"github.com/petabloc/spotify-shuffle/internal/config"
+	"github.com/petabloc/spotify-shuffle/internal/jobs"
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the scheduled jobs from the config file",
+	Long: `Runs in the foreground and performs each job defined under 'jobs' in the config
+file whenever its cron schedule comes due, logging every run. Jobs use the
+stored Spotify login, so log in once with any playlist command first.
+
+Example config:
+  jobs:
+    - name: monday-shuffle
+      schedule: "0 7 * * mon"
+      operation: shuffle
+      playlist: 37i9dQZF1DXcBWIGoYBM5M
+    - name: nightly-fresh
+      schedule: "@daily"
+      operation: fresh
+      playlist: 37i9dQZF1DXcBWIGoYBM5M
+      days: 30
+      target: Fresh Finds
+
+Operations: ` + strings.Join(config.JobOperations, ", ") + `.
+Stop the daemon with Ctrl-C; a job in progress stops after its current request.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+// jobsCmd represents the jobs command group
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "List and run the scheduled jobs from the config file",
+	Long: `Inspect the jobs defined under 'jobs' in the config file, or run one straight away.
+
+Examples:
+  spotify-shuffle jobs list
+  spotify-shuffle jobs run-now monday-shuffle`,
+}
+
+// jobsListCmd represents the jobs list command
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show each job with its schedule and next run",
+	Args:  cobra.NoArgs,
+	RunE:  runJobsList,
+}
+
+// jobsRunNowCmd represents the jobs run-now command
+var jobsRunNowCmd = &cobra.Command{
+	Use:   "run-now <name>",
+	Short: "Run a job immediately",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runJobsRunNow,
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	loaded, err := loadJobs()
+	if err != nil {
+		return err
+	}
+	if len(loaded) == 0 {
+		return fmt.Errorf("no jobs configured; add them under 'jobs' in %s", config.ConfigPath())
+	}
+
+	d, err := newDaemon(cmd.Context(), loaded)
+	if err != nil {
+		return err
+	}
+	return d.Run(cmd.Context())
+}
+
+func runJobsList(cmd *cobra.Command, args []string) error {
+	loaded, err := loadJobs()
+	if err != nil {
+		return err
+	}
+	if len(loaded) == 0 {
+		fmt.Printf("ℹ️  No jobs configured; add them under 'jobs' in %s\n", config.ConfigPath())
+		return nil
+	}
+
+	now := time.Now()
+	for _, job := range loaded {
+		next := "never"
+		if t := job.Schedule.Next(now); !t.IsZero() {
+			next = t.Format("Mon 2006-01-02 15:04")
+		}
+
+		fmt.Printf("📅 %s\n", job.Name)
+		fmt.Printf("   Schedule:  %s (next: %s)\n", job.Schedule, next)
+		fmt.Printf("   Operation: %s\n", job.Describe())
+		fmt.Printf("   Playlist:  %s\n", job.PlaylistID)
+	}
+	return nil
+}
+
+func runJobsRunNow(cmd *cobra.Command, args []string) error {
+	loaded, err := loadJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, job := range loaded {
+		if job.Name == args[0] {
+			d, err := newDaemon(cmd.Context(), loaded)
+			if err != nil {
+				return err
+			}
+			return d.RunJob(cmd.Context(), job)
+		}
+	}
+
+	var names []string
+	for _, job := range loaded {
+		names = append(names, job.Name)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no job named %q; no jobs are configured", args[0])
+	}
+	return fmt.Errorf("no job named %q (available: %s)", args[0], strings.Join(names, ", "))
+}
+
+// loadJobs builds the jobs defined in the config file
+func loadJobs() ([]*jobs.Job, error) {
+	var loaded []*jobs.Job
+	seen := make(map[string]bool)
+	for _, c := range config.GetJobs() {
+		if seen[c.Name] {
+			return nil, fmt.Errorf("invalid jobs config: job name %q is used more than once", c.Name)
+		}
+		seen[c.Name] = true
+
+		job, err := jobs.New(c, spotify.ID(extractPlaylistID(c.Playlist)), config.GetDefaults())
+		if err != nil {
+			return nil, fmt.Errorf("invalid jobs config: %w", err)
+		}
+		loaded = append(loaded, job)
+	}
+	return loaded, nil
+}
+
+// newDaemon creates a daemon that runs jobs with the stored login and logs
+// to stdout
+func newDaemon(ctx context.Context, loaded []*jobs.Job) (*jobs.Daemon, error) {
+	client, err := getStoredClient(auth.ScopesPlaylistModify...)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	newJobManager := func() (*playlist.Manager, func()) {
+		return newManager(client)
+	}
+	return jobs.NewDaemon(loaded, newJobManager, log.New(os.Stdout, "", log.LstdFlags)), nil
+}
+
+// getStoredClient returns a client for the stored login, failing instead of
+// starting a browser login when there is none or it lacks scopes, since
+// nobody is around to complete one
+func getStoredClient(scopes ...string) (*spotify.Client, error) {
+	spotifyAuth, err := newSpotifyAuth(scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := spotifyAuth.Status()
+	if err != nil {
+		return nil, fmt.Errorf("no stored Spotify login; run a playlist command such as 'spotify-shuffle shuffle --playlist <id>' once to log in: %w", err)
+	}
+	if missing := auth.MissingScopes(status.Scopes, scopes); len(missing) > 0 {
+		return nil, fmt.Errorf("stored Spotify login lacks permissions %s; run a playlist command once to grant them", strings.Join(missing, ", "))
+	}
+
+	return spotifyAuth.StoredClient()
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(jobsCmd)
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsRunNowCmd)
+}
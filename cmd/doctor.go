@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/auth"
+	"github.com/petabloc/spotify-shuffle/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// maxClockSkew is the largest clock difference tolerated before token
+// expiry checks become unreliable
+const maxClockSkew = 2 * time.Minute
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose configuration and connectivity problems",
+	Long: `Runs a series of checks (config validity, credentials, redirect URI port,
+clock skew, Spotify API reachability and the stored login) and prints an
+actionable fix for each problem found.`,
+	Args:         cobra.NoArgs,
+	RunE:         runDoctor,
+	SilenceUsage: true,
+}
+
+// checkResult is the outcome of a single doctor check
+type checkResult struct {
+	name   string
+	ok     bool
+	warn   bool
+	detail string
+	fix    string
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	fmt.Println("🩺 Spotify Shuffle doctor")
+	fmt.Println("=========================")
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	results := []checkResult{
+		checkConfigFile(),
+		checkCredentials(),
+		checkRedirectPort(),
+	}
+	results = append(results, checkSpotifyReachability(httpClient)...)
+	results = append(results, checkStoredLogin())
+
+	failed := 0
+	for _, r := range results {
+		icon := "✅"
+		switch {
+		case !r.ok && r.warn:
+			icon = "⚠️ "
+		case !r.ok:
+			icon = "❌"
+			failed++
+		}
+
+		fmt.Printf("%s %s: %s\n", icon, r.name, r.detail)
+		if !r.ok && r.fix != "" {
+			fmt.Printf("   👉 %s\n", r.fix)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+
+	fmt.Println("\n✅ Everything looks good!")
+	return nil
+}
+
+func checkConfigFile() checkResult {
+	r := checkResult{name: "Config file"}
+
+	problems, err := config.Validate()
+	switch {
+	case err != nil:
+		r.detail = fmt.Sprintf("cannot read %s: %v", config.ConfigPath(), err)
+		r.fix = "Check the file permissions or point --config at a readable file"
+	case len(problems) > 0:
+		r.detail = fmt.Sprintf("%d problem(s), first: %s", len(problems), problems[0])
+		r.fix = "Run 'spotify-shuffle config validate' for the full list and 'spotify-shuffle config edit' to fix them"
+	default:
+		r.ok = true
+		r.detail = config.ConfigPath()
+	}
+	return r
+}
+
+func checkCredentials() checkResult {
+	r := checkResult{name: "Credentials"}
+
+	if !config.IsConfigured() {
+		r.detail = "client ID or secret missing or still set to the placeholder"
+		r.fix = "Run 'spotify-shuffle config set spotify.client_id <id>' and 'spotify-shuffle config set spotify.client_secret <secret>'"
+		return r
+	}
+
+	r.ok = true
+	r.detail = "client ID and secret are set"
+	return r
+}
+
+func checkRedirectPort() checkResult {
+	r := checkResult{name: "Redirect URI port"}
+
+	redirectURI := config.GetSpotify().RedirectURI
+	addr, _ := auth.NewSpotifyAuth("", "", redirectURI).CallbackAddr()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		r.detail = fmt.Sprintf("cannot listen on %s: %v", addr, err)
+		r.fix = fmt.Sprintf("Stop whatever is using %s, or change spotify.redirect_uri (and the Redirect URI in the Spotify dashboard) to a free port", addr)
+		return r
+	}
+	listener.Close()
+
+	r.ok = true
+	r.detail = fmt.Sprintf("%s is free for the OAuth callback", addr)
+	return r
+}
+
+func checkSpotifyReachability(httpClient *http.Client) []checkResult {
+	api := checkResult{name: "Spotify API"}
+	clock := checkResult{name: "Clock skew"}
+
+	resp, err := httpClient.Get("https://api.spotify.com/v1/")
+	if err != nil {
+		api.detail = fmt.Sprintf("unreachable: %v", err)
+		api.fix = "Check your internet connection, proxy settings (HTTPS_PROXY) and firewall"
+		clock.warn = true
+		clock.detail = "skipped, the API could not be reached"
+		return []checkResult{api, clock}
+	}
+	resp.Body.Close()
+
+	// Any HTTP answer (usually 401 without a token) means the API is reachable
+	api.ok = true
+	api.detail = fmt.Sprintf("reachable (HTTP %d)", resp.StatusCode)
+
+	skew, err := clockSkew(resp.Header.Get("Date"), time.Now())
+	switch {
+	case err != nil:
+		clock.warn = true
+		clock.detail = fmt.Sprintf("could not determine: %v", err)
+	case skew > maxClockSkew || skew < -maxClockSkew:
+		clock.detail = fmt.Sprintf("local clock is off by %s", skew.Round(time.Second))
+		clock.fix = "Synchronise your system clock (e.g. enable NTP); tokens will otherwise appear expired or valid at the wrong times"
+	default:
+		clock.ok = true
+		clock.detail = fmt.Sprintf("%s, within tolerance", skew.Round(time.Second))
+	}
+
+	return []checkResult{api, clock}
+}
+
+func checkStoredLogin() checkResult {
+	r := checkResult{name: "Stored login"}
+
+	spotifyAuth, err := newSpotifyAuth()
+	if err != nil {
+		r.warn = true
+		r.detail = "skipped, credentials are not configured"
+		return r
+	}
+
+	status, err := spotifyAuth.Status()
+	if err != nil {
+		r.warn = true
+		r.detail = "not logged in yet"
+		r.fix = "Run any playlist command, e.g. 'spotify-shuffle interactive', to log in"
+		return r
+	}
+
+	if !status.HasRefreshToken && status.Expiry.Before(time.Now()) {
+		r.detail = "token expired and cannot be refreshed"
+		r.fix = fmt.Sprintf("Delete %s and log in again", status.File)
+		return r
+	}
+
+	r.ok = true
+	r.detail = fmt.Sprintf("token stored with %d scope(s); run 'spotify-shuffle auth status' for details", len(status.Scopes))
+	return r
+}
+
+// clockSkew returns how far the local clock is ahead of the server's,
+// based on an HTTP Date header
+func clockSkew(dateHeader string, now time.Time) (time.Duration, error) {
+	if dateHeader == "" {
+		return 0, fmt.Errorf("server sent no Date header")
+	}
+
+	serverTime, err := http.ParseTime(dateHeader)
+	if err != nil {
+		return 0, err
+	}
+	return now.Sub(serverTime), nil
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
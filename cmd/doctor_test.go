@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"in sync", "Sun, 01 Mar 2026 12:00:00 GMT", 0, false},
+		{"local clock ahead", "Sun, 01 Mar 2026 11:55:00 GMT", 5 * time.Minute, false},
+		{"local clock behind", "Sun, 01 Mar 2026 12:00:30 GMT", -30 * time.Second, false},
+		{"missing header", "", 0, true},
+		{"garbage header", "yesterday", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skew, err := clockSkew(tt.header, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("clockSkew() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("clockSkew() error = %v", err)
+			}
+			if skew != tt.expected {
+				t.Errorf("clockSkew() = %v, want %v", skew, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDescribeExpiry(t *testing.T) {
+	now := time.Now()
+
+	if got := describeExpiry(time.Time{}, now); got != "never" {
+		t.Errorf("describeExpiry(zero) = %q, want %q", got, "never")
+	}
+
+	future := describeExpiry(now.Add(90*time.Second), now)
+	if want := "(in 1m30s)"; !strings.Contains(future, want) {
+		t.Errorf("describeExpiry(future) = %q, want it to contain %q", future, want)
+	}
+
+	past := describeExpiry(now.Add(-time.Hour), now)
+	if want := "(expired 1h0m0s ago)"; !strings.Contains(past, want) {
+		t.Errorf("describeExpiry(past) = %q, want it to contain %q", past, want)
+	}
+}
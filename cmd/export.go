@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	exportFormat string
+	exportOut    string
+	exportWhere  string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export playlist tracks as CSV or JSON",
+	Long: `Writes the tracks of a playlist to a file or stdout as CSV or JSON.
+
+--where exports only the tracks matching a filter expression, in the same
+language as 'remove --where', e.g. --where 'year < 1990 and not explicit'.
+
+This command only reads data. Public playlists can be read without logging in:
+when no login is stored, the app's client credentials are used instead.`,
+	RunE: runExport,
+}
+
+// exportedTrack is the serialised form of a track
+type exportedTrack struct {
+	Position int      `json:"position"`
+	Name     string   `json:"name"`
+	Artists  []string `json:"artists"`
+	URI      string   `json:"uri"`
+	AddedAt  string   `json:"added_at,omitempty"`
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "csv" && exportFormat != "json" {
+		return fmt.Errorf("invalid format: %s (use 'csv' or 'json')", exportFormat)
+	}
+	if err := requireSinglePlaylist("export"); err != nil {
+		return err
+	}
+
+	var where *playlist.Expr
+	if exportWhere != "" {
+		var err error
+		if where, err = playlist.ParseExpr(exportWhere); err != nil {
+			return fmt.Errorf("invalid --where expression: %w", err)
+		}
+	}
+
+	if exportOut == "-" {
+		// Keep stdout clean for the exported data
+		statusOut = os.Stderr
+	}
+
+	return runPublicPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		tracks, err := manager.GetPlaylistTracks(ctx, playlistID)
+		if err != nil {
+			return fmt.Errorf("failed to get playlist tracks: %w", err)
+		}
+		if where != nil {
+			tracks = playlist.FilterTracks(tracks, where, time.Now())
+		}
+
+		// Only create the file once there is something to write, so a
+		// failed login or fetch doesn't leave an empty one behind
+		var out io.Writer = os.Stdout
+		if exportOut != "-" {
+			f, err := os.Create(exportOut)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if err := writeTracks(out, exportFormat, tracks); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+
+		if exportOut != "-" {
+			fmt.Fprintf(statusOut, "✅ Exported %d tracks to %s\n", len(tracks), exportOut)
+		}
+		return nil
+	})
+}
+
+// writeTracks serialises tracks in the given format
+func writeTracks(w io.Writer, format string, tracks []playlist.Track) error {
+	exported := make([]exportedTrack, len(tracks))
+	for i, track := range tracks {
+		exported[i] = exportedTrack{
+			Position: i + 1,
+			Name:     track.Name,
+			Artists:  track.Artists,
+			URI:      string(track.URI),
+		}
+		if !track.AddedAt.IsZero() {
+			exported[i].AddedAt = track.AddedAt.Format(time.RFC3339)
+		}
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(exported)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"position", "name", "artists", "uri", "added_at"})
+	for _, t := range exported {
+		cw.Write([]string{strconv.Itoa(t.Position), t.Name, strings.Join(t.Artists, "; "), t.URI, t.AddedAt})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "Export format: 'csv' or 'json'")
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "-", "Output file ('-' for stdout)")
+	exportCmd.Flags().StringVar(&exportWhere, "where", "", "Export only tracks matching a filter expression")
+}
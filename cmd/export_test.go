@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+func TestWriteTracks(t *testing.T) {
+	tracks := []playlist.Track{
+		{ID: "1", Name: "Song, With Comma", Artists: []string{"A", "B"}, URI: "spotify:track:1",
+			AddedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{ID: "2", Name: "Plain", Artists: []string{"C"}, URI: "spotify:track:2"},
+	}
+
+	var csvOut bytes.Buffer
+	if err := writeTracks(&csvOut, "csv", tracks); err != nil {
+		t.Fatalf("writeTracks(csv) error = %v", err)
+	}
+	wantCSV := "position,name,artists,uri,added_at\n" +
+		"1,\"Song, With Comma\",A; B,spotify:track:1,2026-01-02T03:04:05Z\n" +
+		"2,Plain,C,spotify:track:2,\n"
+	if csvOut.String() != wantCSV {
+		t.Errorf("writeTracks(csv) =\n%s\nwant\n%s", csvOut.String(), wantCSV)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeTracks(&jsonOut, "json", tracks); err != nil {
+		t.Fatalf("writeTracks(json) error = %v", err)
+	}
+	var decoded []exportedTrack
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("writeTracks(json) produced invalid JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[1].Position != 2 || decoded[1].AddedAt != "" {
+		t.Errorf("writeTracks(json) = %+v, unexpected", decoded)
+	}
+	if strings.Contains(jsonOut.String(), `"added_at": ""`) {
+		t.Error("writeTracks(json) should omit empty added_at")
+	}
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/auth"
+	"github.com/petabloc/spotify-shuffle/internal/history"
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	watchInterval time.Duration
+	logLimit      int
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:     "watch",
+	Aliases: []string{"track-changes"},
+	Short:   "Record snapshots of a playlist to track its changes",
+	Long: `Records the current tracks of a playlist in the local history store, so 'log'
+can later show what was added and removed, when, and by whom. A snapshot is
+only stored when the playlist has changed since the last one.
+
+Without --interval a single snapshot is taken, which suits cron. With
+--interval the command keeps running and takes a snapshot every interval
+until interrupted. Batch options record several playlists at once.
+
+Examples:
+  spotify-shuffle watch --playlist name:"Team Mix"
+  spotify-shuffle watch --playlist 37i9dQZF1DXcBWIGoYBM5M --interval 1h
+  spotify-shuffle track-changes --all-owned`,
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+}
+
+// logCmd represents the log command
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the recorded change history of a playlist",
+	Long: `Shows the snapshots recorded by 'watch', newest first, with the tracks added
+and removed in each and the user who added them. Without --playlist, lists
+the playlists that have a history.
+
+Spotify doesn't say who removed a track; removed tracks show who originally
+added them.
+
+Examples:
+  spotify-shuffle log
+  spotify-shuffle log --playlist name:"Team Mix" --limit 5
+  spotify-shuffle log --playlist 37i9dQZF1DXcBWIGoYBM5M --output json`,
+	Args: cobra.NoArgs,
+	RunE: runLog,
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if watchInterval != 0 && watchInterval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m")
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	record := func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		return recordSnapshot(ctx, manager, store, playlistID)
+	}
+
+	for {
+		err := runPublicPlaylistCommand(ctx, record)
+		if watchInterval == 0 {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Keep watching through failures; the next snapshot may succeed
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		}
+
+		fmt.Fprintf(statusOut, "⏳ Next snapshot at %s\n", time.Now().Add(watchInterval).Format("15:04:05"))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// recordSnapshot stores the playlist's current tracks in the history and
+// reports what changed
+func recordSnapshot(ctx context.Context, manager *playlist.Manager, store *history.Store, playlistID spotify.ID) error {
+	snapshotID, name, tracks, err := manager.PlaylistWithTracks(ctx, playlistID)
+	if err != nil {
+		return err
+	}
+
+	entry, err := store.Record(string(playlistID), history.Snapshot{ID: snapshotID, Name: name, Tracks: tracks}, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record snapshot: %w", err)
+	}
+
+	switch {
+	case entry == nil:
+		fmt.Fprintln(statusOut, "ℹ️  No changes since the last snapshot")
+	case entry.Initial:
+		fmt.Fprintf(statusOut, "📸 Recorded first snapshot with %d tracks\n", entry.Tracks)
+	default:
+		fmt.Fprintf(statusOut, "📸 Recorded snapshot: %d added, %d removed\n", len(entry.Added), len(entry.Removed))
+	}
+	return nil
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if logLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if err := requireSinglePlaylist("log"); err != nil {
+		return err
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+	jsonOutput := getOutputFormat() == "json"
+
+	if len(playlistIDs) == 0 {
+		summaries, err := store.Playlists()
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return writeJSON(os.Stdout, summaries)
+		}
+		printHistorySummaries(os.Stdout, summaries)
+		return nil
+	}
+
+	ref, err := requirePlaylistID()
+	if err != nil {
+		return err
+	}
+	playlistID := ref
+	if strings.HasPrefix(string(ref), playlist.NamePrefix) {
+		// Only name references need Spotify; the history itself is local
+		client, err := getAuthenticatedClient(ctx, auth.ScopesPlaylistRead...)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		if playlistID, err = resolvePlaylistRef(ctx, client, string(ref)); err != nil {
+			return err
+		}
+	}
+
+	entries, err := store.Log(string(playlistID))
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no history recorded for playlist %s; record snapshots with 'spotify-shuffle watch --playlist %s'", playlistID, playlistID)
+	}
+
+	// Newest first, like git log
+	newest := make([]history.Entry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		newest = append(newest, entries[i])
+		if logLimit > 0 && len(newest) == logLimit {
+			break
+		}
+	}
+
+	if jsonOutput {
+		return writeJSON(os.Stdout, newest)
+	}
+	printHistoryLog(os.Stdout, newest)
+	return nil
+}
+
+// printHistoryLog prints entries in the order given, one block per
+// snapshot
+func printHistoryLog(w io.Writer, entries []history.Entry) {
+	for i, entry := range entries {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "📸 snapshot %s\n", entry.SnapshotID)
+		fmt.Fprintf(w, "Date:     %s\n", entry.Time.Local().Format("Mon Jan 2 15:04:05 2006"))
+		fmt.Fprintf(w, "Playlist: %s (%d tracks)\n", entry.Name, entry.Tracks)
+
+		if entry.Initial {
+			fmt.Fprintf(w, "\n    First snapshot, %d tracks\n", len(entry.Added))
+			continue
+		}
+		if len(entry.Added) == 0 && len(entry.Removed) == 0 {
+			fmt.Fprintln(w, "\n    No tracks added or removed (reordered or edited)")
+			continue
+		}
+
+		fmt.Fprintln(w)
+		for _, item := range entry.Added {
+			fmt.Fprintf(w, "    + %s\n", describeHistoryItem(item))
+		}
+		for _, item := range entry.Removed {
+			fmt.Fprintf(w, "    - %s\n", describeHistoryItem(item))
+		}
+	}
+}
+
+// printHistorySummaries lists the playlists that have a history
+func printHistorySummaries(w io.Writer, summaries []history.Summary) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "ℹ️  No playlist history recorded yet; start with 'spotify-shuffle watch --playlist <id>'")
+		return
+	}
+
+	fmt.Fprintf(w, "📚 Playlists with history:\n\n")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "   %s (%s)\n", s.Name, s.PlaylistID)
+		fmt.Fprintf(w, "      %d snapshots from %s to %s, %d tracks now\n",
+			s.Entries, s.First.Local().Format("2006-01-02"), s.Last.Local().Format("2006-01-02"), s.Tracks)
+	}
+}
+
+// describeHistoryItem formats a track with who added it and when
+func describeHistoryItem(item history.Item) string {
+	s := item.Name
+	if len(item.Artists) > 0 {
+		s += " - " + strings.Join(item.Artists, ", ")
+	}
+
+	var details []string
+	if item.AddedBy != "" {
+		details = append(details, "added by "+item.AddedBy)
+	}
+	if !item.AddedAt.IsZero() {
+		details = append(details, item.AddedAt.Local().Format("2006-01-02"))
+	}
+	if len(details) > 0 {
+		s += " (" + strings.Join(details, ", ") + ")"
+	}
+	return s
+}
+
+// openHistory opens the history store in its default location
+func openHistory() (*history.Store, error) {
+	dir, err := history.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate history directory: %w", err)
+	}
+	return history.Open(dir)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(logCmd)
+
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 0, "Keep running and take a snapshot this often (e.g. 30m, 6h)")
+	logCmd.Flags().IntVar(&logLimit, "limit", 0, "Show only the most recent snapshots (0 for all)")
+}
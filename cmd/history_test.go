@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/history"
+)
+
+func TestPrintHistoryLog(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []history.Entry{
+		{
+			Time: at, SnapshotID: "s2", Name: "Team Mix", Tracks: 2,
+			Added:   []history.Item{{Name: "New Song", Artists: []string{"A", "B"}, AddedBy: "carol", AddedAt: at}},
+			Removed: []history.Item{{Name: "Old Song", AddedBy: "alice"}},
+		},
+		{Time: at, SnapshotID: "s1", Name: "Team Mix", Tracks: 5, Initial: true, Added: make([]history.Item, 5)},
+	}
+
+	var buf bytes.Buffer
+	printHistoryLog(&buf, entries)
+	out := buf.String()
+
+	for _, want := range []string{
+		"📸 snapshot s2",
+		"+ New Song - A, B (added by carol, ",
+		"- Old Song (added by alice)",
+		"📸 snapshot s1",
+		"First snapshot, 5 tracks",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "s2") > strings.Index(out, "s1") {
+		t.Error("entries should be printed in the order given")
+	}
+}
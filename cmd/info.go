@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	infoGenres        bool
+	infoGroupGenres   bool
+	infoAudioFeatures bool
+	infoTop           int
+)
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:     "info",
+	Aliases: []string{"stats"},
+	Short:   "Show playlist statistics",
+	Long: `Shows statistics for a playlist: total and average duration, explicit share,
+top artists and albums, a release-year histogram, when tracks were added,
+who added them (for collaborative playlists), and optionally the genre
+distribution and average audio features. Use --output json for the full
+data in machine-readable form.
+
+This command only reads data. Public playlists can be read without logging in:
+when no login is stored, the app's client credentials are used instead.`,
+	RunE: runInfo,
+}
+
+// playlistStats is the JSON form of the info output
+type playlistStats struct {
+	PlaylistID spotify.ID `json:"playlist_id"`
+	*playlist.Stats
+}
+
+func runInfo(cmd *cobra.Command, args []string) error {
+	if infoTop < 1 {
+		return fmt.Errorf("--top must be at least 1")
+	}
+
+	jsonOutput := getOutputFormat() == "json"
+	if jsonOutput {
+		statusOut = os.Stderr
+	}
+
+	return runPublicPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		stats, err := manager.PlaylistStats(ctx, playlistID, playlist.StatsOptions{
+			Genres:        infoGenres || infoGroupGenres,
+			GroupGenres:   infoGroupGenres,
+			AudioFeatures: infoAudioFeatures,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get playlist statistics: %w", err)
+		}
+
+		if jsonOutput {
+			enc := json.NewEncoder(os.Stdout)
+			if !batchRequested() {
+				enc.SetIndent("", "  ")
+			}
+			return enc.Encode(playlistStats{PlaylistID: playlistID, Stats: stats})
+		}
+
+		printStats(os.Stdout, stats, infoTop)
+		return nil
+	})
+}
+
+// showPlaylistInfo prints the basic statistics of a playlist
+func showPlaylistInfo(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	fmt.Println("ℹ️  Getting playlist information...")
+
+	stats, err := manager.PlaylistStats(ctx, playlistID, playlist.StatsOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	printStats(os.Stdout, stats, 5)
+	return nil
+}
+
+// printStats writes stats as text, listing at most top entries per ranking
+func printStats(w io.Writer, stats *playlist.Stats, top int) {
+	fmt.Fprintf(w, "\n📊 Playlist Statistics:\n")
+	fmt.Fprintf(w, "📍 Total tracks: %d\n", stats.Tracks)
+	if stats.Tracks == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "⏱️  Total duration: %s (average %s per track)\n",
+		progress.FormatDuration(stats.TotalDuration), progress.FormatDuration(stats.AverageDuration()))
+	fmt.Fprintf(w, "👨‍🎤 Unique artists: %d\n", stats.UniqueArtists)
+	fmt.Fprintf(w, "💿 Unique albums: %d\n", stats.UniqueAlbums)
+	fmt.Fprintf(w, "🔞 Explicit: %d tracks (%.1f%%)\n", stats.Explicit, 100*stats.ExplicitShare)
+
+	printRanking(w, "🔥 Top artists", stats.TopArtists, top, stats.Tracks)
+	printRanking(w, "💿 Top albums", stats.TopAlbums, top, stats.Tracks)
+
+	if len(stats.ReleaseYears) > 0 {
+		label, years := "📅 Release years", stats.ReleaseYears
+		if len(years) > 15 {
+			label, years = "📅 Release decades", groupCounts(years, func(year string) string { return year[:3] + "0s" })
+		}
+		printHistogram(w, label, years)
+	}
+
+	if len(stats.AddedByMonth) > 0 {
+		label, months := "🕒 Added per month", stats.AddedByMonth
+		if len(months) > 24 {
+			label, months = "🕒 Added per year", groupCounts(months, func(month string) string { return month[:4] })
+		}
+		printHistogram(w, label, months)
+	}
+
+	if len(stats.Contributors) > 0 {
+		fmt.Fprintf(w, "\n👥 Contributors:\n")
+		for i, c := range stats.Contributors {
+			name := c.ID
+			if c.Name != "" && c.Name != c.ID {
+				name = fmt.Sprintf("%s (%s)", c.Name, c.ID)
+			}
+			fmt.Fprintf(w, "   %d. %s (%d tracks, %.0f%%)\n", i+1, name, c.Count, percent(c.Count, stats.Tracks))
+		}
+	}
+
+	if stats.Genres != nil {
+		if len(stats.Genres) == 0 {
+			fmt.Fprintln(w, "\nℹ️  No genres found in playlist")
+		} else {
+			printRanking(w, fmt.Sprintf("🎵 Top genres (%d total)", len(stats.Genres)), stats.Genres, top, stats.Tracks)
+		}
+	}
+
+	if stats.AudioFeaturesError != "" {
+		fmt.Fprintf(w, "\n⚠️  Audio features unavailable: %s\n", stats.AudioFeaturesError)
+	}
+	if f := stats.AudioFeatures; f != nil && f.Tracks > 0 {
+		fmt.Fprintf(w, "\n🎚️  Audio features (average of %d tracks):\n", f.Tracks)
+		fmt.Fprintf(w, "   Danceability:     %3.0f%%\n", 100*f.Danceability)
+		fmt.Fprintf(w, "   Energy:           %3.0f%%\n", 100*f.Energy)
+		fmt.Fprintf(w, "   Valence (mood):   %3.0f%%\n", 100*f.Valence)
+		fmt.Fprintf(w, "   Acousticness:     %3.0f%%\n", 100*f.Acousticness)
+		fmt.Fprintf(w, "   Instrumentalness: %3.0f%%\n", 100*f.Instrumentalness)
+		fmt.Fprintf(w, "   Speechiness:      %3.0f%%\n", 100*f.Speechiness)
+		fmt.Fprintf(w, "   Liveness:         %3.0f%%\n", 100*f.Liveness)
+		fmt.Fprintf(w, "   Tempo:            %.0f BPM\n", f.Tempo)
+		fmt.Fprintf(w, "   Loudness:         %.1f dB\n", f.Loudness)
+	}
+}
+
+// printRanking prints the first top counts with their share of total
+func printRanking(w io.Writer, title string, counts []playlist.Count, top, total int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for i := 0; i < min(top, len(counts)); i++ {
+		fmt.Fprintf(w, "   %d. %s (%d tracks, %.0f%%)\n", i+1, counts[i].Name, counts[i].Count, percent(counts[i].Count, total))
+	}
+}
+
+// printHistogram prints one bar per count, scaled to the largest
+func printHistogram(w io.Writer, title string, counts []playlist.Count) {
+	const width = 30
+
+	largest, labelWidth := 0, 0
+	for _, c := range counts {
+		largest = max(largest, c.Count)
+		labelWidth = max(labelWidth, len(c.Name))
+	}
+
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, c := range counts {
+		bar := strings.Repeat("█", max(1, width*c.Count/largest))
+		fmt.Fprintf(w, "   %-*s %s %d\n", labelWidth, c.Name, bar, c.Count)
+	}
+}
+
+// groupCounts merges counts whose keys map to the same group, keeping the
+// original order of first appearance
+func groupCounts(counts []playlist.Count, group func(string) string) []playlist.Count {
+	var grouped []playlist.Count
+	index := make(map[string]int)
+	for _, c := range counts {
+		key := group(c.Name)
+		if i, ok := index[key]; ok {
+			grouped[i].Count += c.Count
+			continue
+		}
+		index[key] = len(grouped)
+		grouped = append(grouped, playlist.Count{Name: key, Count: c.Count})
+	}
+	return grouped
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+	infoCmd.Flags().BoolVar(&infoGenres, "genres", false, "Include the genre distribution")
+	infoCmd.Flags().BoolVar(&infoGroupGenres, "group-genres", false, "Include the genre distribution, grouped into parent genres such as Metal or Hip Hop")
+	infoCmd.Flags().BoolVar(&infoAudioFeatures, "audio-features", false, "Include average audio features (danceability, energy, tempo, ...)")
+	infoCmd.Flags().IntVar(&infoTop, "top", 10, "How many artists, albums and genres to list")
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+func TestPrintStats(t *testing.T) {
+	tracks := []playlist.Track{
+		{ID: "1", Name: "One", Artists: []string{"A"}, Album: "X", ReleaseDate: "1999", Duration: 3 * time.Minute, Explicit: true},
+		{ID: "2", Name: "Two", Artists: []string{"A"}, Album: "X", ReleaseDate: "2001", Duration: 5 * time.Minute},
+	}
+	stats := playlist.ComputeStats(tracks)
+	stats.Genres = []playlist.Count{{Name: "rock", Count: 2}}
+	stats.AudioFeaturesError = "Forbidden"
+
+	var out bytes.Buffer
+	printStats(&out, stats, 5)
+
+	for _, want := range []string{
+		"Total tracks: 2",
+		"Total duration: 8:00 (average 4:00 per track)",
+		"Explicit: 1 tracks (50.0%)",
+		"1. A (2 tracks, 100%)",
+		"Release years:",
+		"1999 ██████████████████████████████ 1",
+		"Top genres (1 total)",
+		"Audio features unavailable: Forbidden",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestGroupCounts(t *testing.T) {
+	years := []playlist.Count{{Name: "1987", Count: 1}, {Name: "1989", Count: 2}, {Name: "1991", Count: 4}}
+	got := groupCounts(years, func(year string) string { return year[:3] + "0s" })
+	want := []playlist.Count{{Name: "1980s", Count: 3}, {Name: "1990s", Count: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupCounts() = %v, want %v", got, want)
+	}
+}
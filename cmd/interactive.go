@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/petabloc/spotify-shuffle/internal/auth"
 	"github.com/petabloc/spotify-shuffle/internal/config"
 	"github.com/petabloc/spotify-shuffle/internal/playlist"
 	"github.com/spf13/cobra"
@@ -35,14 +36,20 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get authenticated client
-	client, err := getAuthenticatedClient()
+	client, err := getAuthenticatedClient(cmd.Context(), auth.ScopesPlaylistModify...)
 	if err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 
-	manager := playlist.NewManager(client)
+	manager, done := newManager(client)
+	defer done()
 	reader := bufio.NewReader(os.Stdin)
 
+	// Each menu action gets its own Ctrl-C handling in runAction, so an
+	// interrupted action returns to the menu instead of cancelling every
+	// later one; between actions, Ctrl-C quits as usual
+	stopInterrupt()
+
 	for {
 		// Select playlist
 		selectedPlaylist, err := selectPlaylist(cmd.Context(), client, reader)
@@ -84,7 +91,7 @@ func selectPlaylist(ctx context.Context, client *spotify.Client, reader *bufio.R
 
 	switch choice {
 	case "1":
-		return selectPlaylistManually(reader)
+		return selectPlaylistManually(ctx, client, reader)
 	case "2":
 		return selectFromUserPlaylists(ctx, client, reader)
 	case "3":
@@ -95,8 +102,8 @@ func selectPlaylist(ctx context.Context, client *spotify.Client, reader *bufio.R
 	}
 }
 
-func selectPlaylistManually(reader *bufio.Reader) (*spotify.SimplePlaylist, error) {
-	fmt.Print("🔗 Enter playlist ID or URL: ")
+func selectPlaylistManually(ctx context.Context, client *spotify.Client, reader *bufio.Reader) (*spotify.SimplePlaylist, error) {
+	fmt.Print("🔗 Enter playlist ID, URL or name:\"Playlist Name\": ")
 	input, _ := reader.ReadString('\n')
 	input = strings.TrimSpace(input)
 
@@ -104,12 +111,16 @@ func selectPlaylistManually(reader *bufio.Reader) (*spotify.SimplePlaylist, erro
 		return nil, fmt.Errorf("playlist ID/URL cannot be empty")
 	}
 
-	// Extract ID from URL if needed
-	playlistID := extractPlaylistID(input)
+	// Extract ID from URL, or look the name up
+	playlistID, err := resolvePlaylistRef(ctx, client, input)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return selectPlaylistManually(ctx, client, reader)
+	}
 
 	// Create a simple playlist object (we'll get full details later)
 	return &spotify.SimplePlaylist{
-		ID:   spotify.ID(playlistID),
+		ID:   playlistID,
 		Name: "Selected Playlist",
 	}, nil
 }
@@ -136,7 +147,7 @@ func selectFromUserPlaylistsWithOffset(ctx context.Context, client *spotify.Clie
 	if len(playlists.Playlists) == 0 {
 		if offset == 0 {
 			fmt.Println("ℹ️  No playlists found")
-			return selectPlaylistManually(reader)
+			return selectPlaylistManually(ctx, client, reader)
 		} else {
 			fmt.Println("ℹ️  No more playlists found")
 			return selectFromUserPlaylistsWithOffset(ctx, client, reader, 0) // Go back to first page
@@ -179,7 +190,7 @@ func selectFromUserPlaylistsWithOffset(ctx context.Context, client *spotify.Clie
 	input = strings.TrimSpace(strings.ToLower(input))
 
 	if input == "" {
-		return selectPlaylistManually(reader)
+		return selectPlaylistManually(ctx, client, reader)
 	}
 
 	// Handle exit
@@ -236,29 +247,29 @@ func showPlaylistMenu(ctx context.Context, manager *playlist.Manager, playlist s
 
 		switch choice {
 		case "1":
-			if err := interactiveShuffle(ctx, manager, playlist.ID); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
+			runAction(ctx, func(ctx context.Context) error {
+				return interactiveShuffle(ctx, manager, playlist.ID)
+			})
 		case "2":
-			if err := interactiveSort(ctx, manager, playlist.ID, reader); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
+			runAction(ctx, func(ctx context.Context) error {
+				return interactiveSort(ctx, manager, playlist.ID, reader)
+			})
 		case "3":
-			if err := interactiveReverse(ctx, manager, playlist.ID); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
+			runAction(ctx, func(ctx context.Context) error {
+				return interactiveReverse(ctx, manager, playlist.ID)
+			})
 		case "4":
-			if err := interactiveRemove(ctx, manager, playlist.ID, reader); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
+			runAction(ctx, func(ctx context.Context) error {
+				return interactiveRemove(ctx, manager, playlist.ID, reader)
+			})
 		case "5":
-			if err := interactiveCreate(ctx, manager, playlist.ID, reader); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
+			runAction(ctx, func(ctx context.Context) error {
+				return interactiveCreate(ctx, manager, playlist.ID, reader)
+			})
 		case "6":
-			if err := showPlaylistInfo(ctx, manager, playlist.ID); err != nil {
-				fmt.Printf("❌ Error: %v\n", err)
-			}
+			runAction(ctx, func(ctx context.Context) error {
+				return showPlaylistInfo(ctx, manager, playlist.ID)
+			})
 		case "7":
 			return nil
 		case "8":
@@ -270,6 +281,20 @@ func showPlaylistMenu(ctx context.Context, manager *playlist.Manager, playlist s
 	}
 }
 
+// runAction runs one menu action with a context of its own: the first
+// Ctrl-C cancels just this action, and the handler is disarmed again once
+// it returns
+func runAction(ctx context.Context, action func(context.Context) error) {
+	actionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := notifyInterrupt(cancel)
+	defer stop()
+
+	if err := action(actionCtx); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+	}
+}
+
 func interactiveShuffle(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
 	fmt.Println("🔀 Shuffling playlist...")
 
@@ -640,7 +665,7 @@ func interactiveCreateFresh(ctx context.Context, manager *playlist.Manager, play
 	}
 
 	fmt.Printf("➕ Creating fresh playlist '%s' with tracks from last %d days...\n", name, days)
-	count, err := manager.CreateFreshPlaylist(ctx, playlistID, name, days, false)
+	count, err := manager.CreateFreshPlaylist(ctx, playlistID, name, playlist.FreshOptions{Days: days}, false)
 	if err != nil {
 		return err
 	}
@@ -672,7 +697,7 @@ func interactiveCreateChunk(ctx context.Context, manager *playlist.Manager, play
 	}
 
 	fmt.Printf("➕ Creating chunk playlists with %d tracks each...\n", chunkSize)
-	count, err := manager.CreateChunkPlaylists(ctx, playlistID, baseName, chunkSize, false)
+	count, err := manager.CreateChunkPlaylists(ctx, playlistID, baseName, playlist.ChunkOptions{Size: chunkSize}, false)
 	if err != nil {
 		return err
 	}
@@ -698,7 +723,7 @@ func interactiveCreateGenre(ctx context.Context, manager *playlist.Manager, play
 	}
 
 	fmt.Printf("➕ Creating genre playlist '%s' for %s...\n", name, genre)
-	count, err := manager.CreateGenrePlaylist(ctx, playlistID, name, genre, false)
+	count, err := manager.CreateGenrePlaylist(ctx, playlistID, name, playlist.GenreFilter{Include: []string{genre}}, false)
 	if err != nil {
 		return err
 	}
@@ -707,60 +732,6 @@ func interactiveCreateGenre(ctx context.Context, manager *playlist.Manager, play
 	return nil
 }
 
-func showPlaylistInfo(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
-	fmt.Println("ℹ️  Getting playlist information...")
-
-	tracks, err := manager.GetPlaylistTracks(ctx, playlistID)
-	if err != nil {
-		return fmt.Errorf("failed to get playlist tracks: %w", err)
-	}
-
-	fmt.Printf("\n📊 Playlist Statistics:\n")
-	fmt.Printf("📍 Total tracks: %d\n", len(tracks))
-
-	if len(tracks) > 0 {
-		// Get unique artists
-		artistMap := make(map[string]int)
-		for _, track := range tracks {
-			for _, artist := range track.Artists {
-				artistMap[artist]++
-			}
-		}
-
-		fmt.Printf("👨‍🎤 Unique artists: %d\n", len(artistMap))
-
-		// Show top artists
-		if len(artistMap) > 0 {
-			fmt.Println("\n🔥 Top artists:")
-			type artistCount struct {
-				name  string
-				count int
-			}
-
-			var topArtists []artistCount
-			for name, count := range artistMap {
-				topArtists = append(topArtists, artistCount{name, count})
-			}
-
-			// Simple sort by count (descending)
-			for i := 0; i < len(topArtists); i++ {
-				for j := i + 1; j < len(topArtists); j++ {
-					if topArtists[j].count > topArtists[i].count {
-						topArtists[i], topArtists[j] = topArtists[j], topArtists[i]
-					}
-				}
-			}
-
-			maxShow := min(5, len(topArtists))
-			for i := 0; i < maxShow; i++ {
-				fmt.Printf("   %d. %s (%d tracks)\n", i+1, topArtists[i].name, topArtists[i].count)
-			}
-		}
-	}
-
-	return nil
-}
-
 func confirmAction(action string) bool {
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Printf("⚠️  This will %s. Continue? (y/N): ", action)
@@ -856,11 +827,7 @@ func interactiveSetup() error {
 }
 
 func getConfigPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "~/.spotify-shuffle.yaml"
-	}
-	return home + "/.spotify-shuffle.yaml"
+	return config.ConfigPath()
 }
 
 func init() {
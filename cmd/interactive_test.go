@@ -42,6 +42,11 @@ func TestExtractPlaylistID(t *testing.T) {
 			input:    "  37i9dQZF1DXcBWIGoYBM5M  ",
 			expected: "37i9dQZF1DXcBWIGoYBM5M",
 		},
+		{
+			name:     "name reference",
+			input:    `name:"Road Trip / 2024"`,
+			expected: `name:"Road Trip / 2024"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/petabloc/spotify-shuffle/internal/playlist"
 	"github.com/spf13/cobra"
@@ -18,24 +19,45 @@ var (
 	removeByArtist bool
 	removeDays     int
 	artistName     string
+	removeWhere    string
 )
 
 // removeCmd represents the remove command
 var removeCmd = &cobra.Command{
 	Use:   "remove",
 	Short: "Remove tracks from playlist",
-	Long:  `Remove tracks from playlist by age or artist name.`,
-	RunE:  runRemove,
+	Long: `Remove tracks from playlist by age, artist name, or a filter expression.
+
+--where removes every track matching an expression over track fields:
+
+  remove --where 'artist =~ "Foo" or (added_by = "bob" and added_at < 2025-01-01) or duration > 10m or explicit'
+
+Fields: name, artist, album, added_by, isrc, uri, id, popularity, year,
+duration, age, added_at, release_date and explicit. Operators: = != < <= >
+>= and =~ !~ for regular expressions, combined with and, or, not and
+parentheses. Text compares ignoring case; dates are YYYY-MM-DD; durations
+look like 10m, 3:30 or 90d (age is the time since a track was added).`,
+	RunE: runRemove,
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
-	return runPlaylistCommand(func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
-		if removeByAge && removeByArtist {
-			return fmt.Errorf("cannot use both --age and --artist flags")
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		modes := 0
+		for _, set := range []bool{removeByAge, removeByArtist, removeWhere != ""} {
+			if set {
+				modes++
+			}
+		}
+		if modes > 1 {
+			return fmt.Errorf("use only one of --age, --artist and --where")
+		}
+
+		if modes == 0 {
+			return fmt.Errorf("must specify --age, --artist or --where")
 		}
 
-		if !removeByAge && !removeByArtist {
-			return fmt.Errorf("must specify either --age or --artist")
+		if removeWhere != "" {
+			return removeByExpression(ctx, manager, playlistID)
 		}
 
 		if removeByAge {
@@ -159,10 +181,52 @@ func removeByTrackArtist(ctx context.Context, manager *playlist.Manager, playlis
 	return nil
 }
 
+func removeByExpression(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	expr, err := playlist.ParseExpr(removeWhere)
+	if err != nil {
+		return fmt.Errorf("invalid --where expression: %w", err)
+	}
+
+	tracks, err := manager.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	matched := playlist.FilterTracks(tracks, expr, time.Now())
+	if len(matched) == 0 {
+		fmt.Println("ℹ️  No tracks match the expression")
+		return nil
+	}
+
+	fmt.Printf("🔍 %d of %d tracks match:\n", len(matched), len(tracks))
+	maxShow := 10
+	for i, track := range matched {
+		if i >= maxShow {
+			fmt.Printf("... and %d more\n", len(matched)-maxShow)
+			break
+		}
+		fmt.Printf("  %s - %s\n", track.Name, strings.Join(track.Artists, ", "))
+	}
+
+	if !confirmAction(fmt.Sprintf("permanently remove %d tracks from your playlist", len(matched))) {
+		fmt.Println("❌ Operation cancelled")
+		return nil
+	}
+
+	removedCount, err := manager.RemoveTracksWhere(ctx, playlistID, expr)
+	if err != nil {
+		return fmt.Errorf("failed to remove tracks: %w", err)
+	}
+
+	fmt.Printf("✅ Removed %d tracks!\n", removedCount)
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(removeCmd)
 	removeCmd.Flags().BoolVar(&removeByAge, "age", false, "Remove tracks by age")
 	removeCmd.Flags().BoolVar(&removeByArtist, "artist", false, "Remove tracks by artist")
 	removeCmd.Flags().IntVar(&removeDays, "days", 0, "Number of days (use with --age)")
 	removeCmd.Flags().StringVar(&artistName, "name", "", "Artist name (use with --artist)")
+	removeCmd.Flags().StringVar(&removeWhere, "where", "", "Remove tracks matching a filter expression")
 }
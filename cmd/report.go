@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/report"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	reportHTML string
+	reportTop  int
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write playlist statistics as an HTML report",
+	Long: `Writes a single self-contained HTML file with charts of a playlist's
+statistics: the genre distribution, a release-year histogram, the top
+artists, and how many tracks were added each month. Charts are inline SVG,
+so the file needs no other assets and can be opened offline or shared.
+
+This command only reads data. Public playlists can be read without logging in:
+when no login is stored, the app's client credentials are used instead.
+
+Examples:
+  spotify-shuffle report --playlist 37i9dQZF1DXcBWIGoYBM5M --html report.html
+  spotify-shuffle report --playlist name:"Road Trip" --html - > road-trip.html`,
+	RunE: runReport,
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if reportHTML == "" {
+		return fmt.Errorf("--html is required (use '-' for stdout)")
+	}
+	if reportTop < 1 {
+		return fmt.Errorf("--top must be at least 1")
+	}
+	if err := requireSinglePlaylist("report"); err != nil {
+		return err
+	}
+	if reportHTML == "-" {
+		// Keep stdout clean for the report itself
+		statusOut = os.Stderr
+	}
+
+	return runPublicPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		name, err := manager.PlaylistName(ctx, playlistID)
+		if err != nil {
+			return err
+		}
+
+		stats, err := manager.PlaylistStats(ctx, playlistID, playlist.StatsOptions{Genres: true})
+		if err != nil {
+			return fmt.Errorf("failed to get playlist statistics: %w", err)
+		}
+
+		// Render fully before touching the output, so a failure never leaves
+		// a truncated file behind
+		var buf bytes.Buffer
+		err = report.Render(&buf, report.Report{
+			PlaylistID:   string(playlistID),
+			PlaylistName: name,
+			Generated:    time.Now(),
+			Stats:        stats,
+			Top:          reportTop,
+		})
+		if err != nil {
+			return err
+		}
+
+		if reportHTML == "-" {
+			_, err := buf.WriteTo(os.Stdout)
+			return err
+		}
+		if err := os.WriteFile(reportHTML, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		fmt.Fprintf(statusOut, "✅ Wrote report for %d tracks to %s\n", stats.Tracks, reportHTML)
+		return nil
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportHTML, "html", "", "Output HTML file ('-' for stdout)")
+	reportCmd.Flags().IntVar(&reportTop, "top", 10, "How many artists and genres to chart")
+}
@@ -18,7 +18,7 @@ var reverseCmd = &cobra.Command{
 }
 
 func runReverse(cmd *cobra.Command, args []string) error {
-	return runPlaylistCommand(func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
 		fmt.Println("🔄 Reversing playlist order...")
 
 		if err := manager.ReversePlaylist(ctx, playlistID); err != nil {
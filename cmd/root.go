@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/petabloc/spotify-shuffle/internal/config"
 	"github.com/spf13/cobra"
@@ -10,10 +15,24 @@ import (
 
 var (
 	cfgFile         string
-	playlistID      string
+	playlistIDs     []string
+	playlistsFile   string
+	allOwned        bool
+	matchPattern    string
 	interactiveMode bool
+	noCache         bool
+	outputFormat    string
+	timeout         time.Duration
 )
 
+// cancelTimeout releases the --timeout deadline once the command finishes
+var cancelTimeout context.CancelFunc = func() {}
+
+// stopInterrupt disarms Execute's Ctrl-C handler. Interactive mode calls it
+// and arms its own for each menu action, so Ctrl-C stops one action rather
+// than the whole session.
+var stopInterrupt = func() {}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "spotify-shuffle",
@@ -26,6 +45,18 @@ Examples:
   spotify-shuffle --playlist 37i9dQZF1DXcBWIGoYBM5M
   spotify-shuffle shuffle --playlist 37i9dQZF1DXcBWIGoYBM5M
   spotify-shuffle sort --by title --playlist 37i9dQZF1DXcBWIGoYBM5M`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if outputFormat != "" && outputFormat != "text" && outputFormat != "json" {
+			return fmt.Errorf("invalid output format: %s. Use 'text' or 'json'", outputFormat)
+		}
+
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If interactive flag is set or no arguments provided, run interactive mode
 		if interactiveMode || len(args) == 0 {
@@ -40,7 +71,43 @@ Examples:
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
-	return rootCmd.Execute()
+	// Ctrl-C cancels the command's context so running operations can stop
+	// cleanly; a second Ctrl-C kills the process as usual
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopInterrupt = notifyInterrupt(cancel)
+	defer stopInterrupt()
+
+	defer func() { cancelTimeout() }()
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// notifyInterrupt calls cancel on the first Ctrl-C or SIGTERM and then lets
+// the next one kill the process. The returned stop disarms the handler if
+// it hasn't fired; it is safe to call more than once.
+func notifyInterrupt(cancel context.CancelFunc) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			fmt.Fprintln(os.Stderr, "\n⚠️  Interrupted, stopping after the current request (press Ctrl-C again to force quit)")
+			cancel()
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
 }
 
 func init() {
@@ -48,8 +115,14 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.spotify-shuffle.yaml)")
-	rootCmd.PersistentFlags().StringVarP(&playlistID, "playlist", "p", "", "Spotify playlist ID or URL (required for non-interactive commands)")
+	rootCmd.PersistentFlags().StringArrayVarP(&playlistIDs, "playlist", "p", nil, "Spotify playlist ID, URL or name:\"Playlist Name\" (required for non-interactive commands; repeat for several)")
+	rootCmd.PersistentFlags().StringVar(&playlistsFile, "playlists-file", "", "File listing playlist IDs, URLs or name: references, one per line")
+	rootCmd.PersistentFlags().BoolVar(&allOwned, "all-owned", false, "Run on every playlist you own")
+	rootCmd.PersistentFlags().StringVar(&matchPattern, "match", "", "Run on your playlists whose names match a glob, e.g. 'Team *'")
 	rootCmd.PersistentFlags().BoolVarP(&interactiveMode, "interactive", "i", false, "Run in interactive mode")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Fetch all metadata from Spotify instead of the local cache")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", "", "Output format: text or json (default from config, else text)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Give up after this long, e.g. 30s or 5m (default no limit)")
 }
 
 // initConfig reads in config file and ENV variables if set.
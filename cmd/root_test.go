@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -118,3 +121,32 @@ func validatePlaylistID(input string) (string, error) {
 
 	return input, nil
 }
+
+func TestNotifyInterrupt_Rearms(t *testing.T) {
+	// Interactive mode arms a fresh handler per action, so each one must
+	// catch its own interrupt and leave the next action's context alone
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := notifyInterrupt(cancel)
+
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+			t.Fatalf("failed to send interrupt: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("action %d: interrupt didn't cancel its context", i+1)
+		}
+		stop()
+		stop()
+		cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := notifyInterrupt(cancel)
+	stop()
+	if ctx.Err() != nil {
+		t.Error("stopping the handler cancelled the context")
+	}
+}
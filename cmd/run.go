@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	pipelineFile string
+	runDryRun    bool
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run [step [args]] [/ step [args]]...",
+	Short: "Apply several operations with one download and one write",
+	Long: `Applies a pipeline of steps to the playlist's tracks in memory and writes the
+result back once, instead of downloading and rewriting the playlist per operation.
+
+Steps are separated by "/" on the command line, or listed one per line in a
+pipeline file ('#' starts a comment). Available steps:
+  ` + strings.Join(playlist.StepNames, "\n  ") + `
+
+On the command line, quote a remove-where expression as a whole so quoted
+values inside it survive the shell, e.g. remove-where 'artist = "Daft Punk"'.
+
+Examples:
+  spotify-shuffle run --playlist X remove-age 90 / remove-artist Foo / dedupe / shuffle spread
+  spotify-shuffle run --playlist X --file weekly.pipeline
+  spotify-shuffle run --playlist X --dry-run dedupe / sort artist`,
+	RunE: runRun,
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	steps, err := loadPipeline(args)
+	if err != nil {
+		return err
+	}
+
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		return runPipeline(ctx, manager, playlistID, steps, runDryRun)
+	})
+}
+
+// loadPipeline parses the pipeline from --file or the command arguments
+func loadPipeline(args []string) ([]playlist.Step, error) {
+	if pipelineFile != "" {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("give pipeline steps either as arguments or with --file, not both")
+		}
+
+		f, err := os.Open(pipelineFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pipeline file: %w", err)
+		}
+		defer f.Close()
+
+		steps, err := playlist.ReadPipeline(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline file %s: %w", pipelineFile, err)
+		}
+		return steps, nil
+	}
+
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no pipeline steps given (see 'spotify-shuffle run --help')")
+	}
+
+	steps, err := playlist.ParsePipeline(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %w", err)
+	}
+	return steps, nil
+}
+
+// runPipeline runs steps on a playlist and prints what each step did
+func runPipeline(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID, steps []playlist.Step, dryRun bool) error {
+	fmt.Fprintf(statusOut, "🔗 Running %d step(s)...\n", len(steps))
+
+	result, err := manager.RunPipeline(ctx, playlistID, steps, dryRun)
+	if err != nil {
+		return fmt.Errorf("pipeline failed: %w", err)
+	}
+
+	for _, s := range result.Steps {
+		change := ""
+		if s.After != s.Before {
+			change = fmt.Sprintf(" (-%d)", s.Before-s.After)
+		}
+		fmt.Fprintf(statusOut, "   • %-24s %d → %d tracks%s\n", s.Step, s.Before, s.After, change)
+	}
+
+	switch {
+	case !result.Changed:
+		fmt.Fprintln(statusOut, "ℹ️  Pipeline made no changes; playlist left as is")
+	case dryRun:
+		fmt.Fprintf(statusOut, "🔍 Dry run: playlist would go from %d to %d tracks (nothing written)\n", result.Before, result.After)
+	default:
+		fmt.Fprintf(statusOut, "✅ Playlist updated in one write: %d → %d tracks\n", result.Before, result.After)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	runCmd.Flags().StringVarP(&pipelineFile, "file", "f", "", "Read pipeline steps from a file, one per line")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Show what the pipeline would do without writing")
+}
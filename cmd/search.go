@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/petabloc/spotify-shuffle/internal/auth"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	searchType  string
+	searchOwned bool
+	searchLimit int
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find playlists, tracks and artists by name",
+	Long: `Searches your playlists (owned and followed) with fuzzy name matching, and the
+Spotify catalogue for tracks and artists.
+
+Any playlist found can be used wherever a playlist ID is accepted by
+passing its name, e.g. --playlist name:"Road Trip". The name must match one
+playlist exactly, or be part of a single playlist's name; looser matches
+such as typos are never used on their own, and the candidates are listed
+instead.
+
+Examples:
+  spotify-shuffle search road trip
+  spotify-shuffle search --type playlists --owned "team"
+  spotify-shuffle search --type tracks --limit 5 bohemian rhapsody`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+// searchResults is the JSON form of the search output
+type searchResults struct {
+	Playlists []searchPlaylist `json:"playlists,omitempty"`
+	Tracks    []searchTrack    `json:"tracks,omitempty"`
+	Artists   []searchArtist   `json:"artists,omitempty"`
+}
+
+type searchPlaylist struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Owner  string `json:"owner"`
+	Tracks int    `json:"tracks"`
+	Score  int    `json:"score"`
+}
+
+type searchTrack struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Artists []string `json:"artists"`
+	Album   string   `json:"album"`
+	URI     string   `json:"uri"`
+}
+
+type searchArtist struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Genres []string `json:"genres,omitempty"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	query := strings.Join(args, " ")
+
+	var wantPlaylists, wantTracks, wantArtists bool
+	switch searchType {
+	case "all":
+		wantPlaylists, wantTracks, wantArtists = true, true, true
+	case "playlists":
+		wantPlaylists = true
+	case "tracks":
+		wantTracks = true
+	case "artists":
+		wantArtists = true
+	default:
+		return fmt.Errorf("invalid type: %s (use 'all', 'playlists', 'tracks' or 'artists')", searchType)
+	}
+	if searchLimit < 1 || searchLimit > 50 {
+		return fmt.Errorf("limit must be between 1 and 50")
+	}
+
+	jsonOutput := getOutputFormat() == "json"
+	if jsonOutput {
+		statusOut = os.Stderr
+	}
+
+	// Catalogue searches work without a login; the user's playlists don't
+	var client *spotify.Client
+	var err error
+	if wantPlaylists {
+		client, err = getAuthenticatedClient(ctx, auth.ScopesPlaylistRead...)
+	} else {
+		client, err = getPublicClient(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	manager, done := newManager(client)
+	defer done()
+
+	var results searchResults
+	if wantPlaylists {
+		matches, err := manager.SearchPlaylists(ctx, query, searchOwned)
+		if err != nil {
+			return err
+		}
+		for i, m := range matches {
+			if i == searchLimit {
+				break
+			}
+			results.Playlists = append(results.Playlists, searchPlaylist{
+				ID:     string(m.Playlist.ID),
+				Name:   m.Playlist.Name,
+				Owner:  playlistOwnerName(m.Playlist.Owner),
+				Tracks: int(m.Playlist.Tracks.Total),
+				Score:  m.Score,
+			})
+		}
+	}
+	if wantTracks {
+		tracks, err := manager.SearchTracks(ctx, query, searchLimit)
+		if err != nil {
+			return err
+		}
+		for _, t := range tracks {
+			results.Tracks = append(results.Tracks, searchTrack{
+				ID:      string(t.ID),
+				Name:    t.Name,
+				Artists: t.Artists,
+				Album:   t.Album,
+				URI:     string(t.URI),
+			})
+		}
+	}
+	if wantArtists {
+		artists, err := manager.SearchArtists(ctx, query, searchLimit)
+		if err != nil {
+			return err
+		}
+		for _, a := range artists {
+			results.Artists = append(results.Artists, searchArtist{ID: string(a.ID), Name: a.Name, Genres: a.Genres})
+		}
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	printSearchResults(os.Stdout, query, results, wantPlaylists, wantTracks, wantArtists)
+	return nil
+}
+
+// printSearchResults prints each requested section, noting empty ones
+func printSearchResults(w io.Writer, query string, results searchResults, playlists, tracks, artists bool) {
+	fmt.Fprintf(w, "🔍 Results for %q\n", query)
+
+	if playlists {
+		fmt.Fprintf(w, "\n📋 Playlists\n")
+		if len(results.Playlists) == 0 {
+			fmt.Fprintln(w, "   No matching playlists")
+		}
+		for i, p := range results.Playlists {
+			fmt.Fprintf(w, "%3d. %s (%d tracks, by %s)\n     %s\n", i+1, p.Name, p.Tracks, p.Owner, p.ID)
+		}
+	}
+
+	if tracks {
+		fmt.Fprintf(w, "\n🎵 Tracks\n")
+		if len(results.Tracks) == 0 {
+			fmt.Fprintln(w, "   No matching tracks")
+		}
+		for i, t := range results.Tracks {
+			fmt.Fprintf(w, "%3d. %s - %s (%s)\n     %s\n", i+1, t.Name, strings.Join(t.Artists, ", "), t.Album, t.URI)
+		}
+	}
+
+	if artists {
+		fmt.Fprintf(w, "\n🎤 Artists\n")
+		if len(results.Artists) == 0 {
+			fmt.Fprintln(w, "   No matching artists")
+		}
+		for i, a := range results.Artists {
+			line := fmt.Sprintf("%3d. %s", i+1, a.Name)
+			if len(a.Genres) > 0 {
+				line += " (" + strings.Join(a.Genres, ", ") + ")"
+			}
+			fmt.Fprintf(w, "%s\n     %s\n", line, a.ID)
+		}
+	}
+}
+
+// playlistOwnerName returns the owner's display name, or their ID when
+// they have none
+func playlistOwnerName(owner spotify.User) string {
+	if owner.DisplayName != "" {
+		return owner.DisplayName
+	}
+	return owner.ID
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringVar(&searchType, "type", "all", "What to search: all, playlists, tracks or artists")
+	searchCmd.Flags().BoolVar(&searchOwned, "owned", false, "Only search playlists you own")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 10, "Maximum results per section (1-50)")
+}
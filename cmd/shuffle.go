@@ -18,7 +18,7 @@ var shuffleCmd = &cobra.Command{
 }
 
 func runShuffle(cmd *cobra.Command, args []string) error {
-	return runPlaylistCommand(func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
 		fmt.Println("🔀 Shuffling playlist...")
 
 		if err := manager.ShufflePlaylist(ctx, playlistID); err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/petabloc/spotify-shuffle/internal/config"
 	"github.com/petabloc/spotify-shuffle/internal/playlist"
 	"github.com/spf13/cobra"
 	"github.com/zmb3/spotify/v2"
@@ -20,7 +21,11 @@ var sortCmd = &cobra.Command{
 }
 
 func runSort(cmd *cobra.Command, args []string) error {
-	return runPlaylistCommand(func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+	if !cmd.Flags().Changed("by") && config.GetDefaults().SortBy != "" {
+		sortBy = config.GetDefaults().SortBy
+	}
+
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
 		switch sortBy {
 		case "title":
 			fmt.Println("🔤 Sorting playlist by title...")
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/spf13/cobra"
+	"github.com/zmb3/spotify/v2"
+)
+
+var (
+	splitBy           string
+	splitMinTracks    int
+	splitName         string
+	splitNameTemplate string
+	splitGroupGenres  bool
+	splitOverwrite    bool
+	splitDryRun       bool
+)
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Create one playlist per genre, decade, artist or contributor",
+	Long: `Splits a playlist into one new playlist per group, in a single pass:
+
+  genre     one playlist per genre; a track with several genres goes in each
+  decade    by release decade, e.g. "1990s"
+  artist    by the track's first artist
+  added-by  by who added the track, for collaborative playlists
+
+Groups with fewer than --min-tracks tracks are left out. Playlists are
+named "<name> - <group>", where --name defaults to the source playlist's
+name; --name-template changes this, with the group as {label}. Genres are
+looked up once for the whole playlist; --group-genres splits by parent
+genre (Metal, Hip Hop, ...) instead of Spotify's micro-genres.
+
+Existing playlists with the same name are left alone unless --overwrite is
+given. Use --dry-run to see the groups first.
+
+Examples:
+  spotify-shuffle split --by decade --playlist "Liked Archive"
+  spotify-shuffle split --by genre --group-genres --min-tracks 10 --playlist "Everything"
+  spotify-shuffle split --by added-by --name-template "{label}'s picks" --playlist "Team Mix"`,
+	Args: cobra.NoArgs,
+	RunE: runSplit,
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	if err := requireSinglePlaylist("split"); err != nil {
+		return err
+	}
+
+	opts := playlist.SplitOptions{
+		By:           splitBy,
+		MinTracks:    splitMinTracks,
+		GroupGenres:  splitGroupGenres,
+		NameTemplate: splitNameTemplate,
+	}
+	if err := opts.Check(); err != nil {
+		return err
+	}
+
+	return runPlaylistCommand(cmd.Context(), func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error {
+		fmt.Printf("🔍 Grouping tracks by %s...\n", splitBy)
+
+		chunks, small, err := manager.PlanSplitPlaylists(ctx, playlistID, splitName, opts)
+		if err != nil {
+			return fmt.Errorf("failed to plan split: %w", err)
+		}
+		if small > 0 {
+			fmt.Printf("ℹ️  Leaving out %d groups with fewer than %d tracks\n", small, splitMinTracks)
+		}
+		if len(chunks) == 0 {
+			fmt.Println("ℹ️  No groups to create playlists for")
+			return nil
+		}
+
+		if splitDryRun {
+			for _, chunk := range chunks {
+				fmt.Printf("🔍 Would create '%s' with %d tracks\n", chunk.Name, len(chunk.Tracks))
+			}
+			return nil
+		}
+
+		fmt.Printf("➕ Creating %d playlists: %s\n", len(chunks), splitSummary(chunks, 5))
+		created, err := manager.CreatePlannedPlaylists(ctx, chunks, splitOverwrite)
+		if err != nil {
+			return fmt.Errorf("failed to create split playlists: %w", err)
+		}
+
+		if skipped := len(chunks) - created; skipped > 0 {
+			fmt.Printf("⚠️  %d playlists already existed or failed (use --overwrite to replace existing ones)\n", skipped)
+		}
+		fmt.Printf("✅ Created %d playlists!\n", created)
+		return nil
+	})
+}
+
+// splitSummary lists the first few group labels, e.g. "rock, pop and 3 more"
+func splitSummary(chunks []playlist.Chunk, limit int) string {
+	var labels []string
+	for i, chunk := range chunks {
+		if i == limit {
+			return strings.Join(labels, ", ") + fmt.Sprintf(" and %d more", len(chunks)-limit)
+		}
+		labels = append(labels, chunk.Label)
+	}
+	return strings.Join(labels, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().StringVar(&splitBy, "by", "", "How to group tracks: "+strings.Join(playlist.SplitKeys, ", ")+" (required)")
+	splitCmd.Flags().IntVar(&splitMinTracks, "min-tracks", 1, "Leave out groups with fewer tracks")
+	splitCmd.Flags().StringVar(&splitName, "name", "", "Base name for the new playlists (default: the source playlist's name)")
+	splitCmd.Flags().StringVar(&splitNameTemplate, "name-template", playlist.DefaultSplitNameTemplate, "Playlist names; placeholders {base}, {label}, {index}, {n}, {total}, {date}")
+	splitCmd.Flags().BoolVar(&splitGroupGenres, "group-genres", false, "Split by parent genre, such as Metal or Hip Hop, rather than Spotify's micro-genres")
+	splitCmd.Flags().BoolVar(&splitOverwrite, "overwrite", false, "Overwrite existing playlists with the same names")
+	splitCmd.Flags().BoolVar(&splitDryRun, "dry-run", false, "List the playlists that would be created without creating them")
+
+	splitCmd.MarkFlagRequired("by")
+}
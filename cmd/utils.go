@@ -3,51 +3,162 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/petabloc/spotify-shuffle/internal/auth"
 	"github.com/petabloc/spotify-shuffle/internal/config"
 	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/progress"
 	"github.com/zmb3/spotify/v2"
 )
 
 // PlaylistCommandFunc represents a function that operates on a playlist
 type PlaylistCommandFunc func(ctx context.Context, manager *playlist.Manager, playlistID spotify.ID) error
 
-// runPlaylistCommand is a helper that sets up auth and runs a playlist command
-func runPlaylistCommand(fn PlaylistCommandFunc) error {
-	// Extract playlist ID from URL if needed
-	pid := extractPlaylistID(playlistID)
-	if pid == "" {
-		return fmt.Errorf("playlist ID or URL is required. Use --playlist flag or run in interactive mode with 'spotify-shuffle interactive'")
+// statusOut receives the informational lines printed around a command, so
+// commands that write data to stdout can move them out of the way
+var statusOut io.Writer = os.Stdout
+
+// runPlaylistCommand is a helper that sets up auth and runs a playlist command.
+// Commands declare the OAuth scopes they need; playlist modify access is
+// assumed when none are given.
+func runPlaylistCommand(ctx context.Context, fn PlaylistCommandFunc, scopes ...string) error {
+	if !batchRequested() {
+		if _, err := requirePlaylistID(); err != nil {
+			return err
+		}
+	}
+
+	if len(scopes) == 0 {
+		scopes = auth.ScopesPlaylistModify
 	}
 
 	// Get authenticated client
-	client, err := getAuthenticatedClient()
+	client, err := getAuthenticatedClient(ctx, scopes...)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	return runOnTargets(ctx, client, fn)
+}
+
+// runPublicPlaylistCommand runs a command that only reads public data. It
+// never starts a browser login; see getPublicClient.
+func runPublicPlaylistCommand(ctx context.Context, fn PlaylistCommandFunc) error {
+	if !batchRequested() {
+		if _, err := requirePlaylistID(); err != nil {
+			return err
+		}
+	}
+
+	client, err := getPublicClient(ctx)
 	if err != nil {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 
-	ctx := context.Background()
+	return runOnTargets(ctx, client, fn)
+}
+
+// runOnTargets runs fn on the single --playlist, or on every selected
+// playlist in batch mode
+func runOnTargets(ctx context.Context, client *spotify.Client, fn PlaylistCommandFunc) error {
+	if batchRequested() {
+		return runBatch(ctx, client, fn)
+	}
+
+	ref, err := requirePlaylistID()
+	if err != nil {
+		return err
+	}
+	pid, err := resolvePlaylistRef(ctx, client, string(ref))
+	if err != nil {
+		return err
+	}
+	return runWithClient(ctx, client, pid, fn)
+}
+
+// resolvePlaylistRef turns a playlist ID, URL, URI or name:"Playlist Name"
+// reference into a playlist ID
+func resolvePlaylistRef(ctx context.Context, client *spotify.Client, input string) (spotify.ID, error) {
+	return playlist.NewManager(client).ResolvePlaylistRef(ctx, extractPlaylistID(input))
+}
+
+// requirePlaylistID returns the playlist reference from the --playlist
+// flag. Name references are returned as given; see resolvePlaylistRef.
+func requirePlaylistID() (spotify.ID, error) {
+	var input string
+	if len(playlistIDs) > 0 {
+		input = playlistIDs[0]
+	}
 
+	// Extract playlist ID from URL if needed
+	pid := extractPlaylistID(input)
+	if pid == "" {
+		return "", fmt.Errorf("playlist ID or URL is required. Use --playlist flag or run in interactive mode with 'spotify-shuffle interactive'")
+	}
+	return spotify.ID(pid), nil
+}
+
+// runWithClient prints the playlist header and runs fn against it
+func runWithClient(ctx context.Context, client *spotify.Client, pid spotify.ID, fn PlaylistCommandFunc) error {
+	_, err := runOnPlaylist(ctx, client, pid, fn)
+	return err
+}
+
+// runOnPlaylist is runWithClient that also returns the playlist's name,
+// once it is known
+func runOnPlaylist(ctx context.Context, client *spotify.Client, pid spotify.ID, fn PlaylistCommandFunc) (string, error) {
 	// Get playlist info
-	playlistInfo, err := client.GetPlaylist(ctx, spotify.ID(pid))
+	playlistInfo, err := client.GetPlaylist(ctx, pid)
 	if err != nil {
-		return fmt.Errorf("failed to access playlist: %w", err)
+		return "", fmt.Errorf("failed to access playlist: %w", err)
 	}
 
-	fmt.Printf("\n📱 Playlist: %s\n", playlistInfo.Name)
-	fmt.Printf("📊 Total tracks: %d\n", playlistInfo.Tracks.Total)
+	fmt.Fprintf(statusOut, "\n📱 Playlist: %s\n", playlistInfo.Name)
+	fmt.Fprintf(statusOut, "📊 Total tracks: %d\n", playlistInfo.Tracks.Total)
 
 	// Create playlist manager and run command
+	manager, done := newManager(client)
+	defer done()
+	return playlistInfo.Name, fn(ctx, manager, pid)
+}
+
+// newManager creates a playlist manager backed by the metadata cache. Call
+// the returned function once the manager is no longer needed.
+func newManager(client *spotify.Client) (*playlist.Manager, func()) {
 	manager := playlist.NewManager(client)
-	return fn(ctx, manager, spotify.ID(pid))
+	if n := config.GetSpotify().Concurrency; n > 0 {
+		manager.SetConcurrency(n)
+	}
+	manager.SetProgress(progress.New(os.Stderr, getOutputFormat()))
+	c, done := attachCache()
+	manager.SetCache(c)
+	return manager, done
+}
+
+// getOutputFormat returns the --output format, falling back to the
+// configured default
+func getOutputFormat() string {
+	if outputFormat != "" {
+		return outputFormat
+	}
+	if format := config.GetDefaults().OutputFormat; format != "" {
+		return format
+	}
+	return "text"
 }
 
-// extractPlaylistID extracts the playlist ID from a URL or returns the ID as-is
+// extractPlaylistID extracts the playlist ID from a URL or returns the ID
+// as-is. Name references (name:"Road Trip") are returned unchanged.
 func extractPlaylistID(input string) string {
 	input = strings.TrimSpace(input)
 
+	if strings.HasPrefix(input, playlist.NamePrefix) {
+		return input
+	}
+
 	// Handle Spotify URLs
 	if strings.Contains(input, "playlist/") {
 		parts := strings.Split(input, "playlist/")
@@ -70,8 +181,44 @@ func extractPlaylistID(input string) string {
 	return input
 }
 
-// getAuthenticatedClient creates and returns an authenticated Spotify client
-func getAuthenticatedClient() (*spotify.Client, error) {
+// getAuthenticatedClient creates and returns a Spotify client authorised for
+// the given scopes, asking the user to grant any that are missing
+func getAuthenticatedClient(ctx context.Context, scopes ...string) (*spotify.Client, error) {
+	spotifyAuth, err := newSpotifyAuth(scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get authenticated client
+	client, err := spotifyAuth.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	return client, nil
+}
+
+// getPublicClient returns a client for reading public data. A stored user
+// login is reused when there is one so private playlists stay readable;
+// otherwise the app's own client credentials are used, so no interactive
+// login ever happens.
+func getPublicClient(ctx context.Context) (*spotify.Client, error) {
+	spotifyAuth, err := newSpotifyAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	if client, err := spotifyAuth.StoredClient(); err == nil {
+		return client, nil
+	}
+
+	fmt.Fprintln(statusOut, "ℹ️  No Spotify login stored, reading public data with app credentials")
+	spotifyConfig := config.GetSpotify()
+	return auth.NewClientCredentialsClient(ctx, spotifyConfig.ClientID, spotifyConfig.ClientSecret)
+}
+
+// newSpotifyAuth creates an authenticator from the configured credentials
+func newSpotifyAuth(scopes ...string) (*auth.SpotifyAuth, error) {
 	// Get Spotify configuration
 	spotifyConfig := config.GetSpotify()
 	if spotifyConfig.ClientID == "" || spotifyConfig.ClientSecret == "" {
@@ -83,19 +230,10 @@ func getAuthenticatedClient() (*spotify.Client, error) {
 		return nil, fmt.Errorf("please update your Spotify credentials in the config file or run 'spotify-shuffle interactive' for guided setup")
 	}
 
-	// Create authenticator
-	spotifyAuth := auth.NewSpotifyAuth(
+	return auth.NewSpotifyAuth(
 		spotifyConfig.ClientID,
 		spotifyConfig.ClientSecret,
 		spotifyConfig.RedirectURI,
-	)
-
-	// Get authenticated client
-	ctx := context.Background()
-	client, err := spotifyAuth.GetClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to authenticate: %w", err)
-	}
-
-	return client, nil
+		scopes...,
+	), nil
 }
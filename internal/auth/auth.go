@@ -4,16 +4,20 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/zmb3/spotify/v2"
 	spotifyauth "github.com/zmb3/spotify/v2/auth"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 type SpotifyAuth struct {
@@ -23,53 +27,76 @@ type SpotifyAuth struct {
 	redirectURI  string
 	clientID     string
 	clientSecret string
+	scopes       []string
 }
 
-// NewSpotifyAuth creates a new Spotify authenticator
-func NewSpotifyAuth(clientID, clientSecret, redirectURI string) *SpotifyAuth {
+// storedToken is the on-disk representation of an OAuth token together
+// with the scopes the user granted for it
+type storedToken struct {
+	Token  *oauth2.Token `json:"token"`
+	Scopes []string      `json:"scopes"`
+}
+
+// NewSpotifyAuth creates a new Spotify authenticator requesting the given
+// scopes. When no scopes are given, playlist read and modify access is
+// requested.
+func NewSpotifyAuth(clientID, clientSecret, redirectURI string, scopes ...string) *SpotifyAuth {
 	home, _ := os.UserHomeDir()
 	tokenFile := filepath.Join(home, ".spotify-shuffle-token.json")
 
-	auth := spotifyauth.New(
-		spotifyauth.WithRedirectURL(redirectURI),
-		spotifyauth.WithScopes(
-			spotifyauth.ScopePlaylistModifyPublic,
-			spotifyauth.ScopePlaylistModifyPrivate,
-			spotifyauth.ScopePlaylistReadPrivate,
-		),
-		spotifyauth.WithClientID(clientID),
-		spotifyauth.WithClientSecret(clientSecret),
-	)
+	if len(scopes) == 0 {
+		scopes = ScopesPlaylistModify
+	}
 
 	// Generate random state
 	b := make([]byte, 32)
 	rand.Read(b)
 	state := base64.URLEncoding.EncodeToString(b)
 
-	return &SpotifyAuth{
-		auth:         auth,
+	sa := &SpotifyAuth{
 		state:        state,
 		tokenFile:    tokenFile,
 		redirectURI:  redirectURI,
 		clientID:     clientID,
 		clientSecret: clientSecret,
+		scopes:       mergeScopes(scopes),
 	}
+	sa.auth = sa.newAuthenticator(sa.scopes)
+
+	return sa
 }
 
-// GetClient returns an authenticated Spotify client
+// newAuthenticator builds an OAuth authenticator for the given scopes
+func (sa *SpotifyAuth) newAuthenticator(scopes []string) *spotifyauth.Authenticator {
+	return spotifyauth.New(
+		spotifyauth.WithRedirectURL(sa.redirectURI),
+		spotifyauth.WithScopes(scopes...),
+		spotifyauth.WithClientID(sa.clientID),
+		spotifyauth.WithClientSecret(sa.clientSecret),
+	)
+}
+
+// GetClient returns an authenticated Spotify client. A stored token is
+// reused when it covers the requested scopes; otherwise the user is asked
+// to grant the missing ones on top of those already granted.
 func (sa *SpotifyAuth) GetClient(ctx context.Context) (*spotify.Client, error) {
-	// Try to load existing token
-	if token, err := sa.loadToken(); err == nil {
-		client := spotify.New(sa.auth.Client(ctx, token))
-		return client, nil
+	stored, err := sa.loadToken()
+	if err != nil {
+		// Need to authenticate
+		return sa.authenticate(ctx, sa.scopes)
+	}
+
+	if missing := MissingScopes(stored.Scopes, sa.scopes); len(missing) > 0 {
+		fmt.Printf("\n🔐 This command needs additional Spotify permissions: %s\n", strings.Join(missing, ", "))
+		fmt.Println("   Please approve them in your browser; permissions you already granted are kept.")
+		return sa.authenticate(ctx, mergeScopes(stored.Scopes, sa.scopes))
 	}
 
-	// Need to authenticate
-	return sa.authenticate(ctx)
+	return sa.newClient(stored), nil
 }
 
 // authenticate performs the OAuth flow
-func (sa *SpotifyAuth) authenticate(ctx context.Context) (*spotify.Client, error) {
+func (sa *SpotifyAuth) authenticate(ctx context.Context, scopes []string) (*spotify.Client, error) {
 	// Check if context is already cancelled/timed out
 	select {
 	case <-ctx.Done():
@@ -77,14 +104,18 @@ func (sa *SpotifyAuth) authenticate(ctx context.Context) (*spotify.Client, error
 	default:
 	}
 
+	authenticator := sa.newAuthenticator(scopes)
+
 	// Start local server to handle callback
-	ch := make(chan *oauth2.Token)
-	errCh := make(chan error)
+	ch := make(chan *oauth2.Token, 1)
+	errCh := make(chan error, 1)
 
 	// Create HTTP server
-	server := &http.Server{Addr: ":8080"}
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		token, err := sa.auth.Token(ctx, sa.state, r)
+	addr, path := sa.CallbackAddr()
+	mux := http.NewServeMux()
+	server := &http.Server{Addr: addr, Handler: mux}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		token, err := authenticator.Token(ctx, sa.state, r)
 		if err != nil {
 			http.Error(w, "Couldn't get token", http.StatusForbidden)
 			errCh <- err
@@ -112,9 +143,16 @@ func (sa *SpotifyAuth) authenticate(ctx context.Context) (*spotify.Client, error
 		}
 	}()
 
+	// Shutdown server
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
 	// Get auth URL and display to user
-	url := sa.auth.AuthURL(sa.state)
-	fmt.Printf("\n🔐 Please open this URL in your browser to authenticate:\n%s\n\n", url)
+	authURL := authenticator.AuthURL(sa.state)
+	fmt.Printf("\n🔐 Please open this URL in your browser to authenticate:\n%s\n\n", authURL)
 	fmt.Println("Waiting for authentication...")
 
 	// Wait for token, error, or context timeout
@@ -130,32 +168,190 @@ func (sa *SpotifyAuth) authenticate(ctx context.Context) (*spotify.Client, error
 		return nil, fmt.Errorf("authentication timeout")
 	}
 
-	// Shutdown server
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	server.Shutdown(shutdownCtx)
+	sa.auth = authenticator
+
+	// Spotify reports the scopes actually granted alongside the token
+	granted := scopes
+	if s, ok := token.Extra("scope").(string); ok && s != "" {
+		granted = strings.Fields(s)
+	}
+	stored := &storedToken{Token: token, Scopes: mergeScopes(granted)}
 
 	// Save token
-	if err := sa.saveToken(token); err != nil {
+	if err := sa.saveToken(stored); err != nil {
 		log.Printf("Warning: failed to save token: %v", err)
 	}
 
 	// Create client
-	client := spotify.New(sa.auth.Client(context.Background(), token))
-	return client, nil
+	return sa.newClient(stored), nil
+}
+
+// newClient creates a Spotify client that persists refreshed tokens
+func (sa *SpotifyAuth) newClient(stored *storedToken) *spotify.Client {
+	source := oauth2.ReuseTokenSource(stored.Token, &savingTokenSource{sa: sa, stored: stored})
+	// Rate-limited requests wait for Retry-After and are retried
+	return spotify.New(oauth2.NewClient(context.Background(), source), spotify.WithRetry(true))
+}
+
+// CallbackAddr returns the listen address and path for the OAuth callback
+// server, derived from the redirect URI
+func (sa *SpotifyAuth) CallbackAddr() (string, string) {
+	addr, path := ":8080", "/callback"
+
+	u, err := url.Parse(sa.redirectURI)
+	if err != nil {
+		return addr, path
+	}
+	if u.Port() != "" {
+		addr = u.Host
+	}
+	if u.Path != "" {
+		path = u.Path
+	}
+	return addr, path
+}
+
+// TokenFile returns the path of the stored token
+func (sa *SpotifyAuth) TokenFile() string {
+	return sa.tokenFile
+}
+
+// TokenStatus describes the stored token
+type TokenStatus struct {
+	File            string
+	Expiry          time.Time
+	Scopes          []string
+	HasRefreshToken bool
+}
+
+// Status reports on the stored token without contacting Spotify
+func (sa *SpotifyAuth) Status() (*TokenStatus, error) {
+	stored, err := sa.loadToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenStatus{
+		File:            sa.tokenFile,
+		Expiry:          stored.Token.Expiry,
+		Scopes:          stored.Scopes,
+		HasRefreshToken: stored.Token.RefreshToken != "",
+	}, nil
+}
+
+// Refresh exchanges the stored refresh token for a new access token and
+// saves it, returning the new expiry
+func (sa *SpotifyAuth) Refresh(ctx context.Context) (time.Time, error) {
+	stored, err := sa.loadToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if stored.Token.RefreshToken == "" {
+		return time.Time{}, fmt.Errorf("stored token has no refresh token")
+	}
+
+	// Mark the token expired so the authenticator has to refresh it
+	expired := *stored.Token
+	expired.Expiry = time.Now().Add(-time.Minute)
+
+	token, err := sa.auth.RefreshToken(ctx, &expired)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := sa.saveToken(&storedToken{Token: token, Scopes: stored.Scopes}); err != nil {
+		return time.Time{}, fmt.Errorf("failed to save refreshed token: %w", err)
+	}
+	return token.Expiry, nil
+}
+
+// StoredClient returns a client for the stored token without ever
+// starting the OAuth flow
+func (sa *SpotifyAuth) StoredClient() (*spotify.Client, error) {
+	stored, err := sa.loadToken()
+	if err != nil {
+		return nil, err
+	}
+	return sa.newClient(stored), nil
 }
 
 // loadToken loads a saved token from file
-func (sa *SpotifyAuth) loadToken() (*oauth2.Token, error) {
-	// For now, return error to force new authentication
-	// In a real implementation, you'd load from a file
-	return nil, fmt.Errorf("no saved token")
+func (sa *SpotifyAuth) loadToken() (*storedToken, error) {
+	data, err := os.ReadFile(sa.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("no saved token: %w", err)
+	}
+
+	var stored storedToken
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("invalid token file %s: %w", sa.tokenFile, err)
+	}
+	if stored.Token == nil || (stored.Token.AccessToken == "" && stored.Token.RefreshToken == "") {
+		return nil, fmt.Errorf("no saved token")
+	}
+
+	return &stored, nil
 }
 
 // saveToken saves a token to file
-func (sa *SpotifyAuth) saveToken(token *oauth2.Token) error {
-	// For now, just log that we would save it
-	log.Printf("Token would be saved to: %s", sa.tokenFile)
-	// In a real implementation, you'd save to a file
-	return nil
+func (sa *SpotifyAuth) saveToken(stored *storedToken) error {
+	if stored == nil || stored.Token == nil {
+		return fmt.Errorf("no token to save")
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sa.tokenFile), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(sa.tokenFile, data, 0600)
+}
+
+// savingTokenSource refreshes tokens and writes each new one back to the
+// token file so later runs can reuse it
+type savingTokenSource struct {
+	sa     *SpotifyAuth
+	stored *storedToken
+}
+
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.sa.auth.RefreshToken(context.Background(), s.stored.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.AccessToken != s.stored.Token.AccessToken {
+		s.stored = &storedToken{Token: token, Scopes: s.stored.Scopes}
+		if err := s.sa.saveToken(s.stored); err != nil {
+			log.Printf("Warning: failed to save refreshed token: %v", err)
+		}
+	}
+
+	return token, nil
+}
+
+// clientCredentialsTokenURL is the token endpoint for the client credentials grant
+var clientCredentialsTokenURL = spotifyauth.TokenURL
+
+// NewClientCredentialsClient returns a client authenticated as the app
+// itself rather than a user. It needs no browser login but can only read
+// public data such as public playlists, tracks and artists.
+func NewClientCredentialsClient(ctx context.Context, clientID, clientSecret string) (*spotify.Client, error) {
+	cfg := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     clientCredentialsTokenURL,
+	}
+
+	// Fetch a token up front so bad credentials fail here, not mid-command
+	token, err := cfg.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("client credentials grant failed: %w", err)
+	}
+
+	source := oauth2.ReuseTokenSource(token, cfg.TokenSource(context.Background()))
+	return spotify.New(oauth2.NewClient(context.Background(), source), spotify.WithRetry(true)), nil
 }
@@ -2,8 +2,15 @@ package auth
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
+
+	"golang.org/x/oauth2"
 )
 
 func TestNewSpotifyAuth(t *testing.T) {
@@ -46,6 +53,7 @@ func TestSpotifyAuth_GetClient_NoSavedToken(t *testing.T) {
 	// This test verifies that GetClient fails when no token is saved
 	// We use a very short timeout to avoid actually starting the server
 	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:8080/callback")
+	auth.tokenFile = filepath.Join(t.TempDir(), "token.json")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
 	defer cancel()
@@ -59,21 +67,147 @@ func TestSpotifyAuth_GetClient_NoSavedToken(t *testing.T) {
 
 func TestSpotifyAuth_loadToken(t *testing.T) {
 	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:8080/callback")
+	auth.tokenFile = filepath.Join(t.TempDir(), "missing.json")
 
-	// Current implementation always returns error
 	_, err := auth.loadToken()
 	if err == nil {
-		t.Error("Expected error from loadToken() in current implementation")
+		t.Error("Expected error from loadToken() when no token file exists")
 	}
 }
 
 func TestSpotifyAuth_saveToken(t *testing.T) {
 	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:8080/callback")
+	auth.tokenFile = filepath.Join(t.TempDir(), "token.json")
+
+	if err := auth.saveToken(nil); err == nil {
+		t.Error("saveToken(nil) expected error")
+	}
+
+	stored := &storedToken{
+		Token:  &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+		Scopes: []string{"playlist-read-private"},
+	}
+	if err := auth.saveToken(stored); err != nil {
+		t.Fatalf("saveToken() returned unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(auth.tokenFile)
+	if err != nil {
+		t.Fatalf("token file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("token file permissions = %v, want owner-only", perm)
+	}
+
+	loaded, err := auth.loadToken()
+	if err != nil {
+		t.Fatalf("loadToken() error = %v", err)
+	}
+	if loaded.Token.AccessToken != "access" || loaded.Token.RefreshToken != "refresh" {
+		t.Errorf("loadToken() token = %+v, want saved token", loaded.Token)
+	}
+	if !reflect.DeepEqual(loaded.Scopes, stored.Scopes) {
+		t.Errorf("loadToken() scopes = %v, want %v", loaded.Scopes, stored.Scopes)
+	}
+}
+
+func TestSpotifyAuth_GetClient_StoredToken(t *testing.T) {
+	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:8080/callback", ScopesPlaylistRead...)
+	auth.tokenFile = filepath.Join(t.TempDir(), "token.json")
+
+	stored := &storedToken{
+		Token:  &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+		Scopes: ScopesPlaylistModify,
+	}
+	if err := auth.saveToken(stored); err != nil {
+		t.Fatalf("saveToken() error = %v", err)
+	}
+
+	// Stored scopes cover the request, so no OAuth flow is needed
+	client, err := auth.GetClient(context.Background())
+	if err != nil || client == nil {
+		t.Fatalf("GetClient() = %v, %v; want client", client, err)
+	}
+}
+
+func TestSpotifyAuth_GetClient_MissingScopes(t *testing.T) {
+	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:8080/callback", ScopesPlaylistModify...)
+	auth.tokenFile = filepath.Join(t.TempDir(), "token.json")
+
+	stored := &storedToken{
+		Token:  &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)},
+		Scopes: ScopesPlaylistRead,
+	}
+	if err := auth.saveToken(stored); err != nil {
+		t.Fatalf("saveToken() error = %v", err)
+	}
+
+	// Missing scopes force re-consent, which fails immediately on a cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := auth.GetClient(ctx); err == nil {
+		t.Error("GetClient() expected error when stored token lacks scopes")
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required []string
+		expected []string
+	}{
+		{"all granted", []string{"a", "b"}, []string{"a"}, nil},
+		{"some missing", []string{"a"}, []string{"a", "b", "c"}, []string{"b", "c"}},
+		{"nothing granted", nil, []string{"a", "a"}, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MissingScopes(tt.granted, tt.required)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("MissingScopes() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSpotifyAuth_CallbackAddr(t *testing.T) {
+	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:9090/auth/done")
+
+	addr, path := auth.CallbackAddr()
+	if addr != "127.0.0.1:9090" || path != "/auth/done" {
+		t.Errorf("CallbackAddr() = %q, %q; want %q, %q", addr, path, "127.0.0.1:9090", "/auth/done")
+	}
+}
+
+func TestSpotifyAuth_Status(t *testing.T) {
+	auth := NewSpotifyAuth("test_id", "test_secret", "http://127.0.0.1:8080/callback")
+	auth.tokenFile = filepath.Join(t.TempDir(), "token.json")
+
+	if _, err := auth.Status(); err == nil {
+		t.Error("Status() expected error when no token is stored")
+	}
+
+	expiry := time.Now().Add(time.Hour).Round(time.Second)
+	stored := &storedToken{
+		Token:  &oauth2.Token{AccessToken: "access", Expiry: expiry},
+		Scopes: ScopesPlaylistRead,
+	}
+	if err := auth.saveToken(stored); err != nil {
+		t.Fatalf("saveToken() error = %v", err)
+	}
 
-	// Current implementation doesn't actually save, just logs
-	err := auth.saveToken(nil)
+	status, err := auth.Status()
 	if err != nil {
-		t.Errorf("saveToken() returned unexpected error: %v", err)
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Expiry.Equal(expiry) || status.HasRefreshToken || status.File != auth.tokenFile {
+		t.Errorf("Status() = %+v, unexpected", status)
+	}
+
+	if _, err := auth.Refresh(context.Background()); err == nil {
+		t.Error("Refresh() expected error without a refresh token")
 	}
 }
 
@@ -89,3 +223,34 @@ func TestSpotifyAuth_StateGeneration(t *testing.T) {
 		t.Error("State should not be empty")
 	}
 }
+
+func TestNewClientCredentialsClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" {
+			http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+			return
+		}
+		if id, secret, _ := r.BasicAuth(); id != "good_id" || secret != "good_secret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"app_token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	original := clientCredentialsTokenURL
+	clientCredentialsTokenURL = server.URL
+	defer func() { clientCredentialsTokenURL = original }()
+
+	client, err := NewClientCredentialsClient(context.Background(), "good_id", "good_secret")
+	if err != nil || client == nil {
+		t.Fatalf("NewClientCredentialsClient() = %v, %v; want client", client, err)
+	}
+
+	if _, err := NewClientCredentialsClient(context.Background(), "bad_id", "bad_secret"); err == nil {
+		t.Error("NewClientCredentialsClient() expected error for bad credentials")
+	}
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"sort"
+
+	spotifyauth "github.com/zmb3/spotify/v2/auth"
+)
+
+// Scope sets commands can declare as their requirements
+var (
+	// ScopesPlaylistRead allows reading the user's private and collaborative playlists
+	ScopesPlaylistRead = []string{
+		spotifyauth.ScopePlaylistReadPrivate,
+		spotifyauth.ScopePlaylistReadCollaborative,
+	}
+
+	// ScopesPlaylistModify allows reading and rewriting the user's playlists
+	ScopesPlaylistModify = []string{
+		spotifyauth.ScopePlaylistReadPrivate,
+		spotifyauth.ScopePlaylistReadCollaborative,
+		spotifyauth.ScopePlaylistModifyPublic,
+		spotifyauth.ScopePlaylistModifyPrivate,
+	}
+)
+
+// MissingScopes returns the scopes in required that are not in granted
+func MissingScopes(granted, required []string) []string {
+	have := make(map[string]bool, len(granted))
+	for _, s := range granted {
+		have[s] = true
+	}
+
+	var missing []string
+	for _, s := range required {
+		if !have[s] {
+			missing = append(missing, s)
+			have[s] = true
+		}
+	}
+	return missing
+}
+
+// mergeScopes returns the sorted union of the given scope lists
+func mergeScopes(lists ...[]string) []string {
+	set := make(map[string]bool)
+	for _, list := range lists {
+		for _, s := range list {
+			if s != "" {
+				set[s] = true
+			}
+		}
+	}
+
+	merged := make([]string, 0, len(set))
+	for s := range set {
+		merged = append(merged, s)
+	}
+	sort.Strings(merged)
+	return merged
+}
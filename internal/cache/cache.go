@@ -0,0 +1,360 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is an on-disk key-value store for Spotify metadata. Entries are
+// grouped into buckets, each stored as one JSON file, and expire according
+// to the TTL the caller passes to Get. Several processes, such as the
+// daemon and a CLI run, may share one cache directory: Flush merges its
+// changes into the bucket files under a lock.
+type Cache struct {
+	dir     string
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	maxAge  map[string]time.Duration
+	now     func() time.Time
+}
+
+type bucket struct {
+	entries map[string]entry
+	// changed and deleted are the keys this process has written or
+	// removed since the last flush
+	changed map[string]bool
+	deleted map[string]bool
+}
+
+// Bucket files are locked while they are rewritten. A lock older than
+// lockStale was left behind by a process that died mid-write.
+const (
+	lockWait  = 5 * time.Second
+	lockStale = 30 * time.Second
+	lockRetry = 20 * time.Millisecond
+)
+
+type entry struct {
+	Value    json.RawMessage `json:"value"`
+	StoredAt time.Time       `json:"stored_at"`
+}
+
+// BucketStats describes the contents of a single bucket
+type BucketStats struct {
+	Name    string
+	Entries int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// DefaultDir returns the default cache directory for this user
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "spotify-shuffle"), nil
+}
+
+// Open returns a cache rooted at dir, creating the directory if needed
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &Cache{
+		dir:     dir,
+		buckets: make(map[string]*bucket),
+		maxAge:  make(map[string]time.Duration),
+		now:     time.Now,
+	}, nil
+}
+
+// SetMaxAge makes Flush drop entries of a bucket stored longer than age
+// ago, so bucket files don't keep growing with entries Get would no longer
+// return. Buckets without a max age are never pruned.
+func (c *Cache) SetMaxAge(bucketName string, age time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxAge[bucketName] = age
+}
+
+// Dir returns the directory the cache is stored in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get decodes the entry for key into v. It reports false when the entry is
+// missing, older than ttl, or cannot be decoded. A ttl of zero never expires.
+func (c *Cache) Get(bucketName, key string, ttl time.Duration, v interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.load(bucketName)
+	if err != nil {
+		return false
+	}
+
+	e, ok := b.entries[key]
+	if !ok {
+		return false
+	}
+	if ttl > 0 && c.now().Sub(e.StoredAt) > ttl {
+		return false
+	}
+
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Put stores v under key. Changes are kept in memory until Flush.
+func (c *Cache) Put(bucketName, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.load(bucketName)
+	if err != nil {
+		return err
+	}
+
+	b.entries[key] = entry{Value: data, StoredAt: c.now()}
+	b.changed[key] = true
+	delete(b.deleted, key)
+	return nil
+}
+
+// Delete removes key from a bucket
+func (c *Cache) Delete(bucketName, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.load(bucketName)
+	if err != nil {
+		return err
+	}
+
+	delete(b.entries, key)
+	delete(b.changed, key)
+	b.deleted[key] = true
+	return nil
+}
+
+// Flush writes all modified buckets to disk
+func (c *Cache) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, b := range c.buckets {
+		if len(b.changed) == 0 && len(b.deleted) == 0 {
+			continue
+		}
+		if err := c.flushBucket(name, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flushBucket merges this process's changes into the bucket file, which
+// another process may have rewritten since it was loaded, and drops
+// entries past the bucket's max age. Callers must hold c.mu.
+func (c *Cache) flushBucket(name string, b *bucket) error {
+	unlock, err := c.lockBucket(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.readBucket(name)
+	if err != nil {
+		return err
+	}
+	for key := range b.deleted {
+		delete(entries, key)
+	}
+	for key := range b.changed {
+		entries[key] = b.entries[key]
+	}
+	if age := c.maxAge[name]; age > 0 {
+		cutoff := c.now().Add(-age)
+		for key, e := range entries {
+			if e.StoredAt.Before(cutoff) {
+				delete(entries, key)
+			}
+		}
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a torn bucket
+	path := c.bucketPath(name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache bucket %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write cache bucket %s: %w", name, err)
+	}
+
+	b.entries = entries
+	b.changed = make(map[string]bool)
+	b.deleted = make(map[string]bool)
+	return nil
+}
+
+// lockBucket takes a bucket's lock file, waiting up to lockWait for another
+// process to release it, and returns the function that releases it
+func (c *Cache) lockBucket(name string) (func(), error) {
+	path := filepath.Join(c.dir, name+".lock")
+	deadline := time.Now().Add(lockWait)
+
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock cache bucket %s: %w", name, err)
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("cache bucket %s is locked by another process (remove %s if none is running)", name, path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+// Clear removes every bucket from memory and disk
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names, err := c.bucketNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := os.Remove(c.bucketPath(name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	c.buckets = make(map[string]*bucket)
+	return nil
+}
+
+// Stats returns per-bucket statistics, sorted by bucket name
+func (c *Cache) Stats() ([]BucketStats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names, err := c.bucketNames()
+	if err != nil {
+		return nil, err
+	}
+	for name := range c.buckets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var stats []BucketStats
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+
+		b, err := c.load(name)
+		if err != nil {
+			return nil, err
+		}
+
+		s := BucketStats{Name: name, Entries: len(b.entries)}
+		if info, err := os.Stat(c.bucketPath(name)); err == nil {
+			s.Bytes = info.Size()
+		}
+		for _, e := range b.entries {
+			if s.Oldest.IsZero() || e.StoredAt.Before(s.Oldest) {
+				s.Oldest = e.StoredAt
+			}
+			if e.StoredAt.After(s.Newest) {
+				s.Newest = e.StoredAt
+			}
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+// load returns a bucket, reading it from disk on first use. Callers must
+// hold c.mu.
+func (c *Cache) load(name string) (*bucket, error) {
+	if b, ok := c.buckets[name]; ok {
+		return b, nil
+	}
+
+	entries, err := c.readBucket(name)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bucket{entries: entries, changed: make(map[string]bool), deleted: make(map[string]bool)}
+	c.buckets[name] = b
+	return b, nil
+}
+
+// readBucket reads a bucket's entries from disk. A missing bucket is
+// empty, and so is a corrupt one, which is rewritten on the next flush.
+func (c *Cache) readBucket(name string) (map[string]entry, error) {
+	entries := make(map[string]entry)
+	data, err := os.ReadFile(c.bucketPath(name))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return make(map[string]entry), nil
+		}
+	}
+	return entries, nil
+}
+
+// bucketNames lists the buckets stored on disk
+func (c *Cache) bucketNames() ([]string, error) {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			names = append(names, strings.TrimSuffix(f.Name(), ".json"))
+		}
+	}
+	return names, nil
+}
+
+func (c *Cache) bucketPath(name string) string {
+	return filepath.Join(c.dir, name+".json")
+}
@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache_PutGet(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if err := c.Put("artists", "a1", []string{"rock", "indie"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	var genres []string
+	if !c.Get("artists", "a1", time.Hour, &genres) {
+		t.Fatal("Get() = false, want cached entry")
+	}
+	if len(genres) != 2 || genres[0] != "rock" {
+		t.Errorf("Get() value = %v, want [rock indie]", genres)
+	}
+
+	if c.Get("artists", "missing", time.Hour, &genres) {
+		t.Error("Get() = true for missing key")
+	}
+	if c.Get("tracks", "a1", time.Hour, &genres) {
+		t.Error("Get() = true for key in another bucket")
+	}
+
+	if err := c.Delete("artists", "a1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if c.Get("artists", "a1", time.Hour, &genres) {
+		t.Error("Get() = true after Delete()")
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	c, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	if err := c.Put("genres", "t1", "jazz"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	var genre string
+	if c.Get("genres", "t1", time.Hour, &genre) {
+		t.Error("Get() = true for expired entry")
+	}
+	if !c.Get("genres", "t1", 3*time.Hour, &genre) {
+		t.Error("Get() = false for entry within TTL")
+	}
+	if !c.Get("genres", "t1", 0, &genre) {
+		t.Error("Get() = false with zero TTL")
+	}
+}
+
+func TestCache_FlushPersists(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if err := c.Put("albums", "al1", "Blue Train"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "albums.json"))
+	if err != nil {
+		t.Fatalf("bucket file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("bucket file permissions = %v, want owner-only", perm)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	var name string
+	if !reopened.Get("albums", "al1", 0, &name) || name != "Blue Train" {
+		t.Errorf("Get() after reopen = %q, want %q", name, "Blue Train")
+	}
+}
+
+func TestCache_FlushPrunesExpired(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	c.SetMaxAge("genres", 24*time.Hour)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	c.Put("genres", "old", "jazz")
+	c.Put("tracks", "old", "kept")
+	now = now.Add(48 * time.Hour)
+	c.Put("genres", "new", "rock")
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	var v string
+	if reopened.Get("genres", "old", 0, &v) {
+		t.Error("entry past the bucket's max age survived Flush()")
+	}
+	if !reopened.Get("genres", "new", 0, &v) || !reopened.Get("tracks", "old", 0, &v) {
+		t.Error("Flush() dropped entries that haven't expired")
+	}
+}
+
+func TestCache_FlushMergesProcesses(t *testing.T) {
+	dir := t.TempDir()
+	daemon, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	cli, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	daemon.Put("artists", "a1", "daemon")
+	daemon.Put("artists", "gone", "x")
+	if err := daemon.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// The CLI loaded the bucket before the daemon wrote it
+	var v string
+	cli.Get("artists", "a1", 0, &v)
+	cli.Put("artists", "a2", "cli")
+	cli.Delete("artists", "gone")
+	if err := cli.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !reopened.Get("artists", "a1", 0, &v) || v != "daemon" {
+		t.Errorf("daemon's entry = %q, want it kept", v)
+	}
+	if !reopened.Get("artists", "a2", 0, &v) || v != "cli" {
+		t.Errorf("CLI's entry = %q, want it kept", v)
+	}
+	if reopened.Get("artists", "gone", 0, &v) {
+		t.Error("entry deleted by the CLI came back")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "artists.lock")); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestCache_StaleLock(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "tracks.lock")
+	if err := os.WriteFile(lock, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	c.Put("tracks", "t1", "ok")
+	if err := c.Flush(); err != nil {
+		t.Errorf("Flush() with a stale lock error = %v", err)
+	}
+}
+
+func TestCache_CorruptBucket(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tracks.json"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	var v string
+	if c.Get("tracks", "t1", 0, &v) {
+		t.Error("Get() = true from corrupt bucket")
+	}
+	if err := c.Put("tracks", "t1", "ok"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+}
+
+func TestCache_StatsAndClear(t *testing.T) {
+	dir := t.TempDir()
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	c.Put("tracks", "t1", 1)
+	c.Put("tracks", "t2", 2)
+	c.Put("artists", "a1", 3)
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("Stats() returned %d buckets, want 2", len(stats))
+	}
+	if stats[0].Name != "artists" || stats[0].Entries != 1 || stats[1].Name != "tracks" || stats[1].Entries != 2 {
+		t.Errorf("Stats() = %+v, unexpected", stats)
+	}
+	if stats[1].Bytes == 0 || stats[1].Oldest.IsZero() {
+		t.Errorf("Stats() tracks = %+v, want size and timestamps", stats[1])
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	stats, err = c.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if len(stats) != 0 {
+		t.Errorf("Stats() after Clear() = %+v, want empty", stats)
+	}
+}
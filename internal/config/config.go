@@ -1,27 +1,48 @@
 package config
 
 import (
+	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Spotify SpotifyConfig `mapstructure:"spotify"`
+	Version  int            `mapstructure:"version"`
+	Spotify  SpotifyConfig  `mapstructure:"spotify"`
+	Defaults DefaultsConfig `mapstructure:"defaults"`
+	Jobs     []JobConfig    `mapstructure:"jobs"`
 }
 
 type SpotifyConfig struct {
 	ClientID     string `mapstructure:"client_id"`
 	ClientSecret string `mapstructure:"client_secret"`
 	RedirectURI  string `mapstructure:"redirect_uri"`
+	Concurrency  int    `mapstructure:"concurrency"`
+}
+
+// DefaultsConfig holds default values for command flags
+type DefaultsConfig struct {
+	SortBy       string `mapstructure:"sort_by"`
+	ChunkSize    int    `mapstructure:"chunk_size"`
+	FreshDays    int    `mapstructure:"fresh_days"`
+	OutputFormat string `mapstructure:"output_format"`
 }
 
 var cfg Config
 
+// configFile is the path of the config file that was read, if any
+var configFile string
+
 // SetConfigFile sets the config file explicitly
 func SetConfigFile(file string) {
 	viper.SetConfigFile(file)
+	configFile = file
 }
 
 // SetConfigPaths sets the config search paths
@@ -30,6 +51,7 @@ func SetConfigPaths(home string) {
 	viper.AddConfigPath(".")
 	viper.SetConfigType("yaml")
 	viper.SetConfigName(".spotify-shuffle")
+	configFile = ""
 }
 
 // ReadConfig reads the configuration
@@ -44,7 +66,11 @@ func ReadConfig() error {
 	viper.BindEnv("spotify.redirect_uri", "SPOTIFY_REDIRECT_URI")
 
 	// Default values
-	viper.SetDefault("spotify.redirect_uri", "http://127.0.0.1:8080/callback")
+	for _, f := range fields {
+		if f.Default != nil {
+			viper.SetDefault(f.Key, f.Default)
+		}
+	}
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -54,6 +80,8 @@ func ReadConfig() error {
 		if err := createDefaultConfig(); err != nil {
 			return err
 		}
+	} else {
+		configFile = viper.ConfigFileUsed()
 	}
 
 	return viper.Unmarshal(&cfg)
@@ -69,6 +97,29 @@ func GetSpotify() SpotifyConfig {
 	return cfg.Spotify
 }
 
+// GetDefaults returns the default values for command flags
+func GetDefaults() DefaultsConfig {
+	return cfg.Defaults
+}
+
+// GetJobs returns the scheduled jobs run by the daemon
+func GetJobs() []JobConfig {
+	return cfg.Jobs
+}
+
+// ConfigPath returns the path of the config file in use, falling back to
+// the default location in the user's home directory
+func ConfigPath() string {
+	if configFile != "" {
+		return configFile
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".spotify-shuffle.yaml"
+	}
+	return filepath.Join(home, ".spotify-shuffle.yaml")
+}
+
 // createDefaultConfig creates a default config file
 func createDefaultConfig() error {
 	home, err := os.UserHomeDir()
@@ -81,10 +132,28 @@ func createDefaultConfig() error {
 	defaultConfig := `# Spotify Shuffle Configuration
 # Get your credentials from: https://developer.spotify.com/dashboard
 
+version: 1
+
 spotify:
   client_id: "your_spotify_client_id"
   client_secret: "your_spotify_client_secret"
   redirect_uri: "http://127.0.0.1:8080/callback"
+  # Maximum number of API requests made in parallel (1-16)
+  concurrency: 4
+
+# Defaults used when the matching command-line flag is not given
+defaults:
+  sort_by: "title"
+  chunk_size: 250
+  fresh_days: 30
+  output_format: "text"
+
+# Jobs run on a schedule by 'spotify-shuffle daemon', for example:
+# jobs:
+#   - name: monday-shuffle
+#     schedule: "0 7 * * mon"
+#     operation: shuffle
+#     playlist: "37i9dQZF1DXcBWIGoYBM5M"
 
 # You can also set these as environment variables:
 # export SPOTIFY_CLIENT_ID="your_client_id"
@@ -92,7 +161,7 @@ spotify:
 # export SPOTIFY_REDIRECT_URI="http://127.0.0.1:8080/callback"
 `
 
-	return os.WriteFile(configPath, []byte(defaultConfig), 0644)
+	return os.WriteFile(configPath, []byte(defaultConfig), 0600)
 }
 
 // IsConfigured checks if valid Spotify credentials are available
@@ -103,30 +172,171 @@ func IsConfigured() bool {
 		spotify.ClientSecret != "your_spotify_client_secret"
 }
 
-// SaveConfig saves the current configuration to file
+// SaveConfig saves the current configuration to file. Existing keys and
+// comments in the file are preserved; only the values owned by the
+// interactive setup are updated.
 func SaveConfig() error {
-	home, err := os.UserHomeDir()
+	return updateFile(map[string]string{
+		"version":               strconv.Itoa(CurrentVersion),
+		"spotify.client_id":     cfg.Spotify.ClientID,
+		"spotify.client_secret": cfg.Spotify.ClientSecret,
+		"spotify.redirect_uri":  cfg.Spotify.RedirectURI,
+	})
+}
+
+// SetSpotifyConfig updates the Spotify configuration
+func SetSpotifyConfig(clientID, clientSecret, redirectURI string) {
+	cfg.Spotify.ClientID = clientID
+	cfg.Spotify.ClientSecret = clientSecret
+	cfg.Spotify.RedirectURI = redirectURI
+}
+
+// Value returns the effective value of a known key, taking the config
+// file, environment variables and defaults into account
+func Value(key string) (interface{}, error) {
+	if _, ok := LookupField(key); !ok {
+		return nil, fmt.Errorf("unknown config key: %s", key)
+	}
+	return viper.Get(key), nil
+}
+
+// Set validates a value for a known key and writes it to the config file
+func Set(key, raw string) error {
+	f, ok := LookupField(key)
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+
+	value, err := f.Parse(raw)
 	if err != nil {
 		return err
 	}
 
-	configPath := filepath.Join(home, ".spotify-shuffle.yaml")
+	if err := updateFile(map[string]string{key: fmt.Sprint(value)}); err != nil {
+		return err
+	}
 
-	configContent := `# Spotify Shuffle Configuration
-# Generated by interactive setup
+	viper.Set(key, value)
+	return viper.Unmarshal(&cfg)
+}
 
-spotify:
-  client_id: "` + cfg.Spotify.ClientID + `"
-  client_secret: "` + cfg.Spotify.ClientSecret + `"
-  redirect_uri: "` + cfg.Spotify.RedirectURI + `"
-`
+// EnsureFile creates the config file if it does not exist yet
+func EnsureFile() error {
+	if _, err := os.Stat(ConfigPath()); err == nil {
+		return nil
+	}
+	return updateFile(map[string]string{"version": strconv.Itoa(CurrentVersion)})
+}
 
-	return os.WriteFile(configPath, []byte(configContent), 0644)
+// Redact masks a secret value, keeping only its last four characters when
+// the value is long enough for that to be safe
+func Redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return "********"
+	}
+	return "********" + value[len(value)-4:]
 }
 
-// SetSpotifyConfig updates the Spotify configuration
-func SetSpotifyConfig(clientID, clientSecret, redirectURI string) {
-	cfg.Spotify.ClientID = clientID
-	cfg.Spotify.ClientSecret = clientSecret
-	cfg.Spotify.RedirectURI = redirectURI
+// updateFile sets the given dotted keys in the config file, keeping the
+// rest of the document intact
+func updateFile(values map[string]string) error {
+	path := ConfigPath()
+
+	doc, err := loadDocument(path)
+	if err != nil {
+		return err
+	}
+
+	// Apply in schema order so new keys land in a predictable layout
+	for _, f := range fields {
+		value, ok := values[f.Key]
+		if !ok {
+			continue
+		}
+		tag := "!!str"
+		if f.Kind == KindInt {
+			tag = "!!int"
+		}
+		setNodeValue(doc.Content[0], strings.Split(f.Key, "."), value, tag)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// loadDocument parses the config file into a YAML node tree, returning an
+// empty document when the file does not exist yet
+func loadDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		doc = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+		doc.Content[0].HeadComment = "Spotify Shuffle Configuration"
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse %s: top level must be a mapping", path)
+	}
+
+	return &doc, nil
+}
+
+// setNodeValue sets a scalar at path inside a mapping node, creating
+// intermediate mappings as needed
+func setNodeValue(node *yaml.Node, path []string, value, tag string) {
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+		child := node.Content[i+1]
+		if len(path) == 1 {
+			// Keep the existing quoting style when replacing a scalar
+			if child.Kind != yaml.ScalarNode {
+				child.Style = 0
+			}
+			child.Kind = yaml.ScalarNode
+			child.Tag = tag
+			child.Value = value
+			child.Content = nil
+			return
+		}
+		if child.Kind != yaml.MappingNode {
+			*child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		setNodeValue(child, path[1:], value, tag)
+		return
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) == 1 {
+		node.Content = append(node.Content, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value})
+		return
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content, key, child)
+	setNodeValue(child, path[1:], value, tag)
 }
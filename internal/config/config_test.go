@@ -113,6 +113,7 @@ func TestSetConfigFile(t *testing.T) {
 		ClientID:     "file_client_id",
 		ClientSecret: "file_client_secret",
 		RedirectURI:  "http://127.0.0.1:7777/callback",
+		Concurrency:  4,
 	}
 
 	if spotify != expected {
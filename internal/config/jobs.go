@@ -0,0 +1,239 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/schedule"
+)
+
+// JobConfig defines a playlist operation run on a schedule by the daemon
+type JobConfig struct {
+	Name      string `mapstructure:"name"`
+	Schedule  string `mapstructure:"schedule"`
+	Operation string `mapstructure:"operation"`
+	Playlist  string `mapstructure:"playlist"`
+
+	// Operation options; which ones apply depends on the operation
+	Days   int    `mapstructure:"days"`
+	By     string `mapstructure:"by"`
+	Artist string `mapstructure:"artist"`
+	Size   int    `mapstructure:"size"`
+	Genre  string `mapstructure:"genre"`
+	Target string `mapstructure:"target"`
+	Steps  string `mapstructure:"steps"`
+
+	// Chunk options: how to split, minutes per chunk for the duration
+	// strategy, and how to name the chunks
+	Strategy     string `mapstructure:"strategy"`
+	Minutes      int    `mapstructure:"minutes"`
+	NameTemplate string `mapstructure:"name_template"`
+
+	// Genre options: comma-separated genres to leave out, and how genre
+	// and exclude_genre match (contains, exact or regex)
+	ExcludeGenre string `mapstructure:"exclude_genre"`
+	Match        string `mapstructure:"match"`
+}
+
+// JobOperations lists the operations a job can run
+var JobOperations = []string{
+	"shuffle",
+	"sort",
+	"reverse",
+	"remove-age",
+	"remove-artist",
+	"run",
+	"fresh",
+	"chunk",
+	"genre",
+}
+
+// jobKeys lists the keys allowed in a job entry and whether they hold
+// integers
+var jobKeys = map[string]Kind{
+	"name":      KindString,
+	"schedule":  KindString,
+	"operation": KindString,
+	"playlist":  KindString,
+	"days":      KindInt,
+	"by":        KindString,
+	"artist":    KindString,
+	"size":      KindInt,
+	"genre":     KindString,
+	"target":    KindString,
+	"steps":     KindString,
+
+	"strategy":      KindString,
+	"minutes":       KindInt,
+	"name_template": KindString,
+
+	"exclude_genre": KindString,
+	"match":         KindString,
+}
+
+// Check reports the first problem with a job definition: missing fields,
+// an invalid schedule, or options the operation needs but lacks
+func (j JobConfig) Check() error {
+	switch {
+	case j.Name == "":
+		return fmt.Errorf("name is required")
+	case j.Schedule == "":
+		return fmt.Errorf("schedule is required")
+	case j.Operation == "":
+		return fmt.Errorf("operation is required")
+	case j.Playlist == "":
+		return fmt.Errorf("playlist is required")
+	}
+
+	if _, err := schedule.Parse(j.Schedule); err != nil {
+		return err
+	}
+	if j.Days < 0 || j.Size < 0 || j.Minutes < 0 {
+		return fmt.Errorf("days, size and minutes must be positive")
+	}
+
+	switch j.Operation {
+	case "shuffle", "reverse":
+	case "chunk":
+		if j.Strategy != "" && !slices.Contains(playlist.ChunkStrategies, j.Strategy) {
+			return fmt.Errorf("unknown chunk strategy %q (available: %s)", j.Strategy, strings.Join(playlist.ChunkStrategies, ", "))
+		}
+		if err := playlist.CheckChunkNameTemplate(j.NameTemplate); err != nil {
+			return err
+		}
+	case "fresh":
+		if j.By != "" && j.By != "added" && j.By != "released" {
+			return fmt.Errorf("by must be 'added' or 'released'")
+		}
+	case "sort":
+		if j.By != "" && j.By != "title" && j.By != "artist" {
+			return fmt.Errorf("by must be 'title' or 'artist'")
+		}
+	case "remove-age":
+		if j.Days == 0 {
+			return fmt.Errorf("remove-age needs days")
+		}
+	case "remove-artist":
+		if j.Artist == "" {
+			return fmt.Errorf("remove-artist needs artist")
+		}
+	case "run":
+		if j.Steps == "" {
+			return fmt.Errorf("run needs steps")
+		}
+	case "genre":
+		if j.Genre == "" && j.ExcludeGenre == "" {
+			return fmt.Errorf("genre needs genre or exclude_genre")
+		}
+		switch j.Match {
+		case "", "contains", "exact", "regex":
+		default:
+			return fmt.Errorf("match must be 'contains', 'exact' or 'regex'")
+		}
+	default:
+		return fmt.Errorf("unknown operation %q (available: %s)", j.Operation, strings.Join(JobOperations, ", "))
+	}
+
+	switch j.Operation {
+	case "fresh", "chunk", "genre":
+		if j.Target == "" {
+			return fmt.Errorf("%s needs target, the name of the playlist to build", j.Operation)
+		}
+	}
+	return nil
+}
+
+// validateJobs checks the jobs list of the config file
+func validateJobs(v interface{}, problems *[]Problem) {
+	if v == nil {
+		return
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		*problems = append(*problems, Problem{Key: "jobs", Message: "expected a list of jobs"})
+		return
+	}
+
+	names := make(map[string]bool)
+	for i, item := range items {
+		prefix := fmt.Sprintf("jobs[%d]", i)
+
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			*problems = append(*problems, Problem{Key: prefix, Message: "expected a job definition"})
+			continue
+		}
+
+		job, ok := decodeJob(prefix, entry, problems)
+		if !ok {
+			continue
+		}
+		if job.Name != "" {
+			prefix = fmt.Sprintf("jobs[%s]", job.Name)
+		}
+		if names[job.Name] {
+			*problems = append(*problems, Problem{Key: prefix, Message: "duplicate job name"})
+		}
+		if job.Name != "" {
+			names[job.Name] = true
+		}
+		if err := job.Check(); err != nil {
+			*problems = append(*problems, Problem{Key: prefix, Message: err.Error()})
+		}
+	}
+}
+
+// decodeJob converts a decoded YAML job entry into a JobConfig, recording
+// unknown keys and values of the wrong type
+func decodeJob(prefix string, entry map[string]interface{}, problems *[]Problem) (JobConfig, bool) {
+	values := make(map[string]string)
+	ints := make(map[string]int)
+	ok := true
+
+	for k, v := range entry {
+		kind, known := jobKeys[k]
+		if !known {
+			*problems = append(*problems, Problem{Key: prefix + "." + k, Message: "unknown key"})
+			ok = false
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		if kind == KindInt {
+			n, err := strconv.Atoi(fmt.Sprint(v))
+			if err != nil {
+				*problems = append(*problems, Problem{Key: prefix + "." + k, Message: "must be an integer"})
+				ok = false
+				continue
+			}
+			ints[k] = n
+			continue
+		}
+		values[k] = fmt.Sprint(v)
+	}
+
+	return JobConfig{
+		Name:      values["name"],
+		Schedule:  values["schedule"],
+		Operation: values["operation"],
+		Playlist:  values["playlist"],
+		Days:      ints["days"],
+		By:        values["by"],
+		Artist:    values["artist"],
+		Size:      ints["size"],
+		Genre:     values["genre"],
+		Target:    values["target"],
+		Steps:     values["steps"],
+
+		Strategy:     values["strategy"],
+		Minutes:      ints["minutes"],
+		NameTemplate: values["name_template"],
+
+		ExcludeGenre: values["exclude_genre"],
+		Match:        values["match"],
+	}, ok
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestJobConfigCheck(t *testing.T) {
+	valid := JobConfig{Name: "j", Schedule: "@daily", Operation: "shuffle", Playlist: "p"}
+
+	tests := []struct {
+		name    string
+		modify  func(j *JobConfig)
+		wantErr string
+	}{
+		{"valid", func(j *JobConfig) {}, ""},
+		{"missing name", func(j *JobConfig) { j.Name = "" }, "name is required"},
+		{"missing playlist", func(j *JobConfig) { j.Playlist = "" }, "playlist is required"},
+		{"bad schedule", func(j *JobConfig) { j.Schedule = "0 25 * * *" }, "hour"},
+		{"unknown operation", func(j *JobConfig) { j.Operation = "explode" }, "unknown operation"},
+		{"bad sort key", func(j *JobConfig) { j.Operation = "sort"; j.By = "mood" }, "by must be"},
+		{"remove-age without days", func(j *JobConfig) { j.Operation = "remove-age" }, "needs days"},
+		{"run without steps", func(j *JobConfig) { j.Operation = "run" }, "needs steps"},
+		{"fresh without target", func(j *JobConfig) { j.Operation = "fresh" }, "needs target"},
+		{"bad fresh date", func(j *JobConfig) { j.Operation = "fresh"; j.By = "played"; j.Target = "F" }, "by must be 'added' or 'released'"},
+		{"genre with target", func(j *JobConfig) { j.Operation = "genre"; j.Genre = "rock"; j.Target = "Rock" }, ""},
+		{"genre exclude only", func(j *JobConfig) { j.Operation = "genre"; j.ExcludeGenre = "pop"; j.Target = "No Pop" }, ""},
+		{"genre without genres", func(j *JobConfig) { j.Operation = "genre"; j.Target = "Rock" }, "needs genre or exclude_genre"},
+		{"bad genre match", func(j *JobConfig) { j.Operation = "genre"; j.Genre = "rock"; j.Match = "fuzzy"; j.Target = "Rock" }, "match must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := valid
+			tt.modify(&job)
+			err := job.Check()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Check() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadConfigJobs(t *testing.T) {
+	viper.Reset()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := `jobs:
+  - name: nightly-fresh
+    schedule: "0 3 * * *"
+    operation: fresh
+    playlist: 37i9dQZF1DXcBWIGoYBM5M
+    days: 14
+    target: Fresh Finds
+`
+	if err := os.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	SetConfigFile(configFile)
+
+	if err := ReadConfig(); err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+
+	jobs := GetJobs()
+	want := JobConfig{
+		Name:      "nightly-fresh",
+		Schedule:  "0 3 * * *",
+		Operation: "fresh",
+		Playlist:  "37i9dQZF1DXcBWIGoYBM5M",
+		Days:      14,
+		Target:    "Fresh Finds",
+	}
+	if len(jobs) != 1 || jobs[0] != want {
+		t.Errorf("GetJobs() = %+v, want [%+v]", jobs, want)
+	}
+}
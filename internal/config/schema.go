@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config schema version written by SaveConfig
+const CurrentVersion = 1
+
+// Kind describes the type of value a config key holds
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+)
+
+// Field describes a single known configuration key
+type Field struct {
+	Key         string
+	Kind        Kind
+	Default     interface{}
+	Secret      bool
+	Description string
+	check       func(value interface{}) error
+}
+
+// fields is the full configuration schema, in display order
+var fields = []Field{
+	{
+		Key:         "version",
+		Kind:        KindInt,
+		Description: "Config schema version",
+		check: func(v interface{}) error {
+			if n := v.(int); n < 0 || n > CurrentVersion {
+				return fmt.Errorf("unsupported version %d (this build understands up to %d)", n, CurrentVersion)
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "spotify.client_id",
+		Kind:        KindString,
+		Description: "Spotify app client ID",
+	},
+	{
+		Key:         "spotify.client_secret",
+		Kind:        KindString,
+		Secret:      true,
+		Description: "Spotify app client secret",
+	},
+	{
+		Key:         "spotify.redirect_uri",
+		Kind:        KindString,
+		Default:     "http://127.0.0.1:8080/callback",
+		Description: "OAuth redirect URI registered with the Spotify app",
+		check: func(v interface{}) error {
+			u, err := url.Parse(v.(string))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("must be an absolute http(s) URL")
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "spotify.concurrency",
+		Kind:        KindInt,
+		Default:     4,
+		Description: "Maximum number of Spotify API requests made in parallel",
+		check:       intRange(1, 16),
+	},
+	{
+		Key:         "defaults.sort_by",
+		Kind:        KindString,
+		Default:     "title",
+		Description: "Default sort mode for 'sort' ('title' or 'artist')",
+		check:       oneOf("title", "artist"),
+	},
+	{
+		Key:         "defaults.chunk_size",
+		Kind:        KindInt,
+		Default:     250,
+		Description: "Default tracks per chunk for 'create --type chunk'",
+		check:       intRange(1, 10000),
+	},
+	{
+		Key:         "defaults.fresh_days",
+		Kind:        KindInt,
+		Default:     30,
+		Description: "Default number of days for 'create --type fresh'",
+		check:       intRange(1, 36500),
+	},
+	{
+		Key:         "defaults.output_format",
+		Kind:        KindString,
+		Default:     "text",
+		Description: "Default output format ('text' or 'json')",
+		check:       oneOf("text", "json"),
+	},
+}
+
+// Fields returns the configuration schema
+func Fields() []Field {
+	return append([]Field(nil), fields...)
+}
+
+// LookupField returns the schema entry for a key
+func LookupField(key string) (Field, bool) {
+	for _, f := range fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Parse converts a raw string into the field's typed value and validates it
+func (f Field) Parse(raw string) (interface{}, error) {
+	var value interface{} = raw
+	if f.Kind == KindInt {
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", f.Key)
+		}
+		value = n
+	}
+
+	if f.check != nil {
+		if err := f.check(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Key, err)
+		}
+	}
+	return value, nil
+}
+
+// Problem describes a single issue found while validating the config file
+type Problem struct {
+	Key     string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+// Validate checks the config file against the schema and reports unknown
+// keys and invalid values. A missing config file has no problems.
+func Validate() ([]Problem, error) {
+	data, err := os.ReadFile(ConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return []Problem{{Key: "(file)", Message: fmt.Sprintf("invalid YAML: %v", err)}}, nil
+	}
+
+	var problems []Problem
+	validateMap("", raw, &problems)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Key < problems[j].Key
+	})
+	return problems, nil
+}
+
+// validateMap walks a decoded YAML mapping and records problems for each leaf
+func validateMap(prefix string, m map[string]interface{}, problems *[]Problem) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if key == "jobs" {
+			validateJobs(v, problems)
+			continue
+		}
+
+		if child, ok := v.(map[string]interface{}); ok {
+			if !isSection(key) {
+				*problems = append(*problems, Problem{Key: key, Message: "unknown key"})
+				continue
+			}
+			validateMap(key, child, problems)
+			continue
+		}
+
+		f, ok := LookupField(key)
+		if !ok {
+			if isSection(key) {
+				*problems = append(*problems, Problem{Key: key, Message: "expected a section, got a value"})
+			} else {
+				*problems = append(*problems, Problem{Key: key, Message: "unknown key"})
+			}
+			continue
+		}
+
+		if v == nil {
+			continue
+		}
+		if _, err := f.Parse(fmt.Sprint(v)); err != nil {
+			*problems = append(*problems, Problem{Key: key, Message: strings.TrimPrefix(err.Error(), key+": ")})
+		}
+	}
+}
+
+// isSection reports whether key is a known parent of schema keys
+func isSection(key string) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(f.Key, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func oneOf(values ...string) func(interface{}) error {
+	return func(v interface{}) error {
+		for _, allowed := range values {
+			if v.(string) == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of: %s", strings.Join(values, ", "))
+	}
+}
+
+func intRange(lo, hi int) func(interface{}) error {
+	return func(v interface{}) error {
+		if n := v.(int); n < lo || n > hi {
+			return fmt.Errorf("must be between %d and %d", lo, hi)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,314 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantKeys []string
+	}{
+		{
+			name: "valid config",
+			content: `version: 1
+spotify:
+  client_id: "id"
+  client_secret: "secret"
+  redirect_uri: "http://127.0.0.1:8080/callback"
+defaults:
+  sort_by: artist
+  chunk_size: 100
+`,
+			wantKeys: nil,
+		},
+		{
+			name: "unknown keys",
+			content: `spotify:
+  client_id: "id"
+  colour: blue
+extras:
+  foo: bar
+`,
+			wantKeys: []string{"extras", "spotify.colour"},
+		},
+		{
+			name: "bad values",
+			content: `version: 7
+spotify:
+  redirect_uri: "not a url"
+defaults:
+  sort_by: popularity
+  chunk_size: lots
+  fresh_days: 0
+  output_format: xml
+`,
+			wantKeys: []string{
+				"defaults.chunk_size",
+				"defaults.fresh_days",
+				"defaults.output_format",
+				"defaults.sort_by",
+				"spotify.redirect_uri",
+				"version",
+			},
+		},
+		{
+			name: "valid jobs",
+			content: `jobs:
+  - name: monday-shuffle
+    schedule: "0 7 * * mon"
+    operation: shuffle
+    playlist: 37i9dQZF1DXcBWIGoYBM5M
+  - name: nightly-fresh
+    schedule: "@daily"
+    operation: fresh
+    playlist: 37i9dQZF1DXcBWIGoYBM5M
+    days: 30
+    target: Fresh Finds
+`,
+			wantKeys: nil,
+		},
+		{
+			name: "bad jobs",
+			content: `jobs:
+  - name: weekly
+    schedule: "every monday"
+    operation: shuffle
+    playlist: abc
+  - name: weekly
+    schedule: "@weekly"
+    operation: shuffle
+    playlist: abc
+  - name: weekly
+    schedule: "@weekly"
+    operation: shuffle
+    playlist: abc
+    colour: blue
+    days: lots
+  - schedule: "@daily"
+    operation: fresh
+    playlist: abc
+`,
+			wantKeys: []string{"jobs[2].colour", "jobs[2].days", "jobs[3]", "jobs[weekly]", "jobs[weekly]"},
+		},
+		{
+			name: "exclude-only genre job",
+			content: `jobs:
+  - name: no-pop
+    schedule: "@daily"
+    operation: genre
+    playlist: abc
+    exclude_genre: pop, k-pop
+    target: No Pop
+`,
+			wantKeys: nil,
+		},
+		{
+			name: "bad genre match",
+			content: `jobs:
+  - name: rock
+    schedule: "@daily"
+    operation: genre
+    playlist: abc
+    genre: rock
+    match: bogus
+    target: Rock
+`,
+			wantKeys: []string{"jobs[rock]"},
+		},
+		{
+			name: "bad chunk strategy",
+			content: `jobs:
+  - name: chunks
+    schedule: "@weekly"
+    operation: chunk
+    playlist: abc
+    strategy: mood
+    target: Mix
+`,
+			wantKeys: []string{"jobs[chunks]"},
+		},
+		{
+			name: "bad chunk name template",
+			content: `jobs:
+  - name: chunks
+    schedule: "@weekly"
+    operation: chunk
+    playlist: abc
+    strategy: genre
+    name_template: "{base} {genre}"
+    target: Mix
+`,
+			wantKeys: []string{"jobs[chunks]"},
+		},
+		{
+			name:     "jobs given as value",
+			content:  "jobs: nightly\n",
+			wantKeys: []string{"jobs"},
+		},
+		{
+			name:     "section given as value",
+			content:  "defaults: 5\n",
+			wantKeys: []string{"defaults"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configFile, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("Failed to write test config file: %v", err)
+			}
+			SetConfigFile(configFile)
+
+			problems, err := Validate()
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			var gotKeys []string
+			for _, p := range problems {
+				gotKeys = append(gotKeys, p.Key)
+			}
+			if strings.Join(gotKeys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("Validate() problem keys = %v, want %v (problems: %v)", gotKeys, tt.wantKeys, problems)
+			}
+		})
+	}
+}
+
+func TestSaveConfigPreservesFile(t *testing.T) {
+	viper.Reset()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	original := `# My settings
+spotify:
+  client_id: "old_id" # keep me
+  client_secret: "old_secret"
+custom_key: untouched
+`
+	if err := os.WriteFile(configFile, []byte(original), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	SetConfigFile(configFile)
+	if err := ReadConfig(); err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+
+	secret := "se\"cr\net: value"
+	SetSpotifyConfig("new_id", secret, "http://127.0.0.1:9999/callback")
+	if err := SaveConfig(); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	for _, want := range []string{"# My settings", "# keep me", "custom_key: untouched"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Saved config lost %q:\n%s", want, content)
+		}
+	}
+
+	var parsed struct {
+		Version int `yaml:"version"`
+		Spotify struct {
+			ClientID     string `yaml:"client_id"`
+			ClientSecret string `yaml:"client_secret"`
+			RedirectURI  string `yaml:"redirect_uri"`
+		} `yaml:"spotify"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Saved config is not valid YAML: %v", err)
+	}
+	if parsed.Spotify.ClientSecret != secret {
+		t.Errorf("ClientSecret = %q, want %q", parsed.Spotify.ClientSecret, secret)
+	}
+	if parsed.Spotify.ClientID != "new_id" {
+		t.Errorf("ClientID = %q, want %q", parsed.Spotify.ClientID, "new_id")
+	}
+	if parsed.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", parsed.Version, CurrentVersion)
+	}
+}
+
+func TestFieldParse(t *testing.T) {
+	f, ok := LookupField("defaults.chunk_size")
+	if !ok {
+		t.Fatal("defaults.chunk_size missing from schema")
+	}
+
+	value, err := f.Parse("120")
+	if err != nil || value != 120 {
+		t.Errorf("Parse(\"120\") = %v, %v; want 120, nil", value, err)
+	}
+
+	if _, err := f.Parse("0"); err == nil {
+		t.Error("Parse(\"0\") expected error")
+	}
+}
+
+func TestSetAndValue(t *testing.T) {
+	viper.Reset()
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configFile, []byte("# keep\nspotify:\n  client_id: \"id\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+	SetConfigFile(configFile)
+	if err := ReadConfig(); err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+
+	if err := Set("defaults.chunk_size", "75"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got := GetDefaults().ChunkSize; got != 75 {
+		t.Errorf("GetDefaults().ChunkSize = %d, want 75", got)
+	}
+	if value, err := Value("defaults.chunk_size"); err != nil || value != 75 {
+		t.Errorf("Value() = %v, %v; want 75, nil", value, err)
+	}
+
+	if err := Set("defaults.sort_by", "popularity"); err == nil {
+		t.Error("Set() with invalid value expected error")
+	}
+	if err := Set("no.such.key", "x"); err == nil {
+		t.Error("Set() with unknown key expected error")
+	}
+	if _, err := Value("no.such.key"); err == nil {
+		t.Error("Value() with unknown key expected error")
+	}
+
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	if !strings.Contains(string(content), "chunk_size: 75") || !strings.Contains(string(content), "# keep") {
+		t.Errorf("Config file not updated as expected:\n%s", content)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"short", "********"},
+		{"abcdef0123456789", "********6789"},
+	}
+
+	for _, tt := range tests {
+		if got := Redact(tt.input); got != tt.expected {
+			t.Errorf("Redact(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
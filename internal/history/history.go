@@ -0,0 +1,270 @@
+// Package history records snapshots of playlists over time in a local
+// store, so changes to shared playlists can be reviewed later.
+//
+// Each playlist has one append-only file of JSON lines. The first entry
+// holds every track; later entries only hold the tracks added and removed
+// since the entry before, and are written only when Spotify reports a new
+// snapshot of the playlist.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+// Item is a track as recorded in the history
+type Item struct {
+	URI     string    `json:"uri"`
+	Name    string    `json:"name"`
+	Artists []string  `json:"artists,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by,omitempty"`
+}
+
+// Entry is one recorded snapshot of a playlist
+type Entry struct {
+	Time       time.Time `json:"time"`
+	SnapshotID string    `json:"snapshot_id"`
+	Name       string    `json:"name"`
+	Tracks     int       `json:"tracks"`
+	// Initial marks the first snapshot, whose Added lists every track
+	Initial bool   `json:"initial,omitempty"`
+	Added   []Item `json:"added,omitempty"`
+	Removed []Item `json:"removed,omitempty"`
+}
+
+// Snapshot is the current state of a playlist, as passed to Record
+type Snapshot struct {
+	ID     string
+	Name   string
+	Tracks []playlist.Track
+}
+
+// Summary describes the history kept for one playlist
+type Summary struct {
+	PlaylistID string    `json:"playlist_id"`
+	Name       string    `json:"name"`
+	Entries    int       `json:"entries"`
+	Tracks     int       `json:"tracks"`
+	First      time.Time `json:"first"`
+	Last       time.Time `json:"last"`
+}
+
+// Store keeps playlist histories in a directory
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the default history directory for this user. It sits
+// next to the configuration rather than in the cache directory, because
+// the history can't be downloaded again.
+func DefaultDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "spotify-shuffle", "history"), nil
+}
+
+// Open returns a store rooted at dir, creating the directory if needed
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory the history is stored in
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Record compares snap with the last recorded state of the playlist and
+// appends an entry if the snapshot ID has changed. It returns the new
+// entry, or nil if nothing was recorded.
+func (s *Store) Record(playlistID string, snap Snapshot, at time.Time) (*Entry, error) {
+	entries, err := s.Log(playlistID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 && entries[len(entries)-1].SnapshotID == snap.ID {
+		return nil, nil
+	}
+
+	current := make([]Item, len(snap.Tracks))
+	for i, t := range snap.Tracks {
+		current[i] = Item{URI: string(t.URI), Name: t.Name, Artists: t.Artists, AddedAt: t.AddedAt.UTC(), AddedBy: t.AddedBy}
+	}
+
+	entry := Entry{
+		Time:       at.UTC(),
+		SnapshotID: snap.ID,
+		Name:       snap.Name,
+		Tracks:     len(current),
+	}
+	if len(entries) == 0 {
+		entry.Initial = true
+		entry.Added = current
+	} else {
+		entry.Added, entry.Removed = Diff(Replay(entries), current)
+	}
+
+	if err := s.append(playlistID, entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Log returns the recorded entries of a playlist, oldest first. A playlist
+// that has never been recorded has no entries.
+func (s *Store) Log(playlistID string) ([]Entry, error) {
+	f, err := os.Open(s.path(playlistID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to read history of %s, line %d: %w", playlistID, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// Playlists summarises every recorded playlist, most recently changed
+// first
+func (s *Store) Playlists() ([]Summary, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.jsonl"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+
+	var summaries []Summary
+	for _, file := range files {
+		id, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(file), ".jsonl"))
+		if err != nil {
+			continue
+		}
+		entries, err := s.Log(id)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		last := entries[len(entries)-1]
+		summaries = append(summaries, Summary{
+			PlaylistID: id,
+			Name:       last.Name,
+			Entries:    len(entries),
+			Tracks:     last.Tracks,
+			First:      entries[0].Time,
+			Last:       last.Time,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Last.After(summaries[j].Last)
+	})
+	return summaries, nil
+}
+
+// Replay rebuilds the tracks of a playlist as of the last of entries. The
+// order of the tracks is not recorded.
+func Replay(entries []Entry) []Item {
+	var items []Item
+	for _, entry := range entries {
+		if entry.Initial {
+			items = append([]Item(nil), entry.Added...)
+			continue
+		}
+		// Whatever is left after taking out the removed tracks
+		items, _ = Diff(entry.Removed, items)
+		items = append(items, entry.Added...)
+	}
+	return items
+}
+
+// Diff returns the items in after that are not in before, and the items in
+// before that are not in after. A track is matched by URI and the time it
+// was added, so removing a track and adding it again shows as both, and
+// duplicates are counted individually.
+func Diff(before, after []Item) (added, removed []Item) {
+	remaining := make(map[string]int)
+	for _, item := range before {
+		remaining[item.key()]++
+	}
+	for _, item := range after {
+		if remaining[item.key()] > 0 {
+			remaining[item.key()]--
+			continue
+		}
+		added = append(added, item)
+	}
+
+	kept := make(map[string]int)
+	for _, item := range after {
+		kept[item.key()]++
+	}
+	for _, item := range before {
+		if kept[item.key()] > 0 {
+			kept[item.key()]--
+			continue
+		}
+		removed = append(removed, item)
+	}
+	return added, removed
+}
+
+func (i Item) key() string {
+	return i.URI + "|" + i.AddedAt.UTC().Format(time.RFC3339)
+}
+
+// append adds entry as a line at the end of the playlist's history file
+func (s *Store) append(playlistID string, entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path(playlistID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return f.Close()
+}
+
+// path returns the history file of a playlist. IDs are escaped so an
+// unexpected one can't name a file outside the store.
+func (s *Store) path(playlistID string) string {
+	return filepath.Join(s.dir, url.PathEscape(playlistID)+".jsonl")
+}
@@ -0,0 +1,163 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/zmb3/spotify/v2"
+)
+
+var day = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func track(uri, addedBy string, addedDay int) playlist.Track {
+	return playlist.Track{
+		URI:     spotify.URI(uri),
+		Name:    "Song " + uri,
+		Artists: []string{"Artist"},
+		AddedAt: day.AddDate(0, 0, addedDay),
+		AddedBy: addedBy,
+	}
+}
+
+func uris(items []Item) []string {
+	var out []string
+	for _, item := range items {
+		out = append(out, item.URI)
+	}
+	return out
+}
+
+func TestRecordAndLog(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := []playlist.Track{track("a", "alice", 0), track("b", "bob", 1)}
+	entry, err := store.Record("p1", Snapshot{ID: "s1", Name: "Team Mix", Tracks: first}, day)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if entry == nil || !entry.Initial || entry.Tracks != 2 || len(entry.Added) != 2 {
+		t.Fatalf("first entry = %+v, want initial with 2 tracks", entry)
+	}
+
+	// Same snapshot: nothing to record
+	entry, err = store.Record("p1", Snapshot{ID: "s1", Name: "Team Mix", Tracks: first}, day.Add(time.Hour))
+	if err != nil || entry != nil {
+		t.Fatalf("unchanged snapshot recorded %+v, %v", entry, err)
+	}
+
+	second := []playlist.Track{track("b", "bob", 1), track("c", "carol", 2)}
+	entry, err = store.Record("p1", Snapshot{ID: "s2", Name: "Team Mix", Tracks: second}, day.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if got := uris(entry.Added); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("added = %v, want [c]", got)
+	}
+	if got := uris(entry.Removed); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("removed = %v, want [a]", got)
+	}
+	if entry.Removed[0].AddedBy != "alice" {
+		t.Errorf("removed track added by %q, want alice", entry.Removed[0].AddedBy)
+	}
+
+	// The third snapshot is diffed against the replayed second one
+	third := []playlist.Track{track("c", "carol", 2)}
+	entry, err = store.Record("p1", Snapshot{ID: "s3", Name: "Team Mix", Tracks: third}, day.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if len(entry.Added) != 0 || !reflect.DeepEqual(uris(entry.Removed), []string{"b"}) {
+		t.Errorf("third entry added %v removed %v, want removed [b]", uris(entry.Added), uris(entry.Removed))
+	}
+
+	entries, err := store.Log("p1")
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if got := uris(Replay(entries)); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("Replay() = %v, want [c]", got)
+	}
+}
+
+func TestLogWithoutHistory(t *testing.T) {
+	store, _ := Open(t.TempDir())
+
+	entries, err := store.Log("unknown")
+	if err != nil || entries != nil {
+		t.Errorf("Log() = %v, %v; want no entries", entries, err)
+	}
+}
+
+func TestLogCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := Open(dir)
+	os.WriteFile(filepath.Join(dir, "p1.jsonl"), []byte("{not json\n"), 0600)
+
+	if _, err := store.Log("p1"); err == nil {
+		t.Error("Log() should fail on a corrupt history file")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	at := func(d int) time.Time { return day.AddDate(0, 0, d) }
+	before := []Item{{URI: "a", AddedAt: at(0)}, {URI: "a", AddedAt: at(0)}, {URI: "b", AddedAt: at(1)}}
+
+	tests := []struct {
+		name        string
+		after       []Item
+		wantAdded   []string
+		wantRemoved []string
+	}{
+		{"unchanged in another order", []Item{{URI: "b", AddedAt: at(1)}, {URI: "a", AddedAt: at(0)}, {URI: "a", AddedAt: at(0)}}, nil, nil},
+		{"one duplicate removed", []Item{{URI: "a", AddedAt: at(0)}, {URI: "b", AddedAt: at(1)}}, nil, []string{"a"}},
+		{"removed and added again", []Item{{URI: "a", AddedAt: at(0)}, {URI: "a", AddedAt: at(0)}, {URI: "b", AddedAt: at(5)}}, []string{"b"}, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := Diff(before, tt.after)
+			if got := uris(added); !reflect.DeepEqual(got, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", got, tt.wantAdded)
+			}
+			if got := uris(removed); !reflect.DeepEqual(got, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", got, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestPlaylists(t *testing.T) {
+	store, _ := Open(t.TempDir())
+	store.Record("old", Snapshot{ID: "s1", Name: "Old", Tracks: []playlist.Track{track("a", "", 0)}}, day)
+	store.Record("new", Snapshot{ID: "s1", Name: "New"}, day.Add(time.Hour))
+	store.Record("new", Snapshot{ID: "s2", Name: "Renamed", Tracks: []playlist.Track{track("b", "", 0)}}, day.Add(2*time.Hour))
+
+	summaries, err := store.Playlists()
+	if err != nil {
+		t.Fatalf("Playlists() error = %v", err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("got %d playlists, want 2", len(summaries))
+	}
+	if s := summaries[0]; s.PlaylistID != "new" || s.Name != "Renamed" || s.Entries != 2 || s.Tracks != 1 {
+		t.Errorf("first summary = %+v, want the most recently changed playlist", s)
+	}
+}
+
+func TestPathEscapesID(t *testing.T) {
+	store, _ := Open(t.TempDir())
+
+	if got := filepath.Dir(store.path("../../etc/passwd")); got != store.Dir() {
+		t.Errorf("path() escaped the store: %s", got)
+	}
+}
@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+// ManagerFunc returns a playlist manager for one job run and a function to
+// call once the run is over
+type ManagerFunc func() (*playlist.Manager, func())
+
+// Daemon runs jobs whenever their schedules come due
+type Daemon struct {
+	jobs       []*Job
+	newManager ManagerFunc
+	logger     *log.Logger
+	now        func() time.Time
+	next       map[*Job]time.Time
+}
+
+// NewDaemon returns a daemon for jobs that logs to logger
+func NewDaemon(jobs []*Job, newManager ManagerFunc, logger *log.Logger) *Daemon {
+	return &Daemon{
+		jobs:       jobs,
+		newManager: newManager,
+		logger:     logger,
+		now:        time.Now,
+		next:       make(map[*Job]time.Time),
+	}
+}
+
+// Run waits for jobs to come due and runs them until ctx is cancelled.
+// A failing job is logged and does not stop the daemon. Jobs due at the
+// same time run one after another, in config order.
+func (d *Daemon) Run(ctx context.Context) error {
+	d.logger.Printf("🕒 Daemon started with %d jobs", len(d.jobs))
+	d.schedule(d.now())
+	for _, job := range d.jobs {
+		if next, ok := d.next[job]; ok {
+			d.logger.Printf("   %s: next run %s", job.Name, next.Format("Mon 2006-01-02 15:04"))
+		} else {
+			d.logger.Printf("⚠️  %s: schedule %q never matches, job disabled", job.Name, job.Schedule)
+		}
+	}
+
+	for {
+		wake, ok := d.nextWake()
+		if !ok {
+			d.logger.Printf("⚠️  No job has an upcoming run, stopping")
+			return nil
+		}
+
+		timer := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.logger.Printf("🛑 Daemon stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		d.runDue(ctx, d.now())
+	}
+}
+
+// schedule computes the next run after now for every job that has none
+func (d *Daemon) schedule(now time.Time) {
+	for _, job := range d.jobs {
+		if _, ok := d.next[job]; ok {
+			continue
+		}
+		if next := job.Schedule.Next(now); !next.IsZero() {
+			d.next[job] = next
+		}
+	}
+}
+
+// nextWake returns the earliest upcoming run
+func (d *Daemon) nextWake() (time.Time, bool) {
+	var wake time.Time
+	for _, next := range d.next {
+		if wake.IsZero() || next.Before(wake) {
+			wake = next
+		}
+	}
+	return wake, !wake.IsZero()
+}
+
+// runDue runs every job whose next run is at or before now, then
+// schedules its following run. Runs missed while another job was busy are
+// not made up.
+func (d *Daemon) runDue(ctx context.Context, now time.Time) {
+	for _, job := range d.jobs {
+		next, ok := d.next[job]
+		if !ok || next.After(now) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		d.RunJob(ctx, job)
+		delete(d.next, job)
+	}
+	d.schedule(d.now())
+}
+
+// RunJob runs a single job immediately and logs the outcome
+func (d *Daemon) RunJob(ctx context.Context, job *Job) error {
+	d.logger.Printf("▶️  %s: %s", job.Name, job.Describe())
+	start := d.now()
+
+	manager, done := d.newManager()
+	summary, err := job.Run(ctx, manager)
+	done()
+
+	elapsed := d.now().Sub(start).Round(time.Millisecond)
+	if err != nil {
+		d.logger.Printf("❌ %s: %v (after %s)", job.Name, err, elapsed)
+		return err
+	}
+	d.logger.Printf("✅ %s: %s (%s)", job.Name, summary, elapsed)
+	return nil
+}
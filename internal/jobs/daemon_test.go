@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/config"
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestDaemon_RunDue(t *testing.T) {
+	fake, client := newFakeSpotify(t)
+
+	newJob := func(name, schedule string) *Job {
+		job, err := New(config.JobConfig{Name: name, Schedule: schedule, Operation: "reverse", Playlist: "p"}, spotify.ID("p"), testDefaults)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		return job
+	}
+	hourly := newJob("hourly", "@hourly")
+	monday := newJob("monday", "0 7 * * mon")
+
+	var logs bytes.Buffer
+	d := NewDaemon([]*Job{hourly, monday}, func() (*playlist.Manager, func()) {
+		return playlist.NewManager(client), func() {}
+	}, log.New(&logs, "", 0))
+
+	// Wednesday 15 May 2024
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	d.schedule(now)
+
+	if wake, _ := d.nextWake(); !wake.Equal(time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextWake() = %v, want 11:00", wake)
+	}
+
+	now = time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)
+	fake.fail = true
+	d.runDue(context.Background(), now)
+
+	out := logs.String()
+	if !strings.Contains(out, "❌ hourly") {
+		t.Errorf("expected the failed run to be logged, got:\n%s", out)
+	}
+	if strings.Contains(out, "monday") {
+		t.Errorf("monday job should not have run yet, got:\n%s", out)
+	}
+
+	// A failure doesn't stop the job from being scheduled again
+	if next := d.next[hourly]; !next.Equal(time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("next hourly run = %v, want 12:00", next)
+	}
+	if next := d.next[monday]; !next.Equal(time.Date(2024, 5, 20, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("next monday run = %v, want Mon 20 May 07:00", next)
+	}
+
+	fake.fail = false
+	if err := d.RunJob(context.Background(), monday); err != nil {
+		t.Fatalf("RunJob() error = %v", err)
+	}
+	if !strings.Contains(logs.String(), "✅ monday: playlist order reversed") {
+		t.Errorf("expected a success line, got:\n%s", logs.String())
+	}
+}
+
+func TestDaemon_StopsOnCancel(t *testing.T) {
+	job, err := New(config.JobConfig{Name: "j", Schedule: "@yearly", Operation: "shuffle", Playlist: "p"}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var logs bytes.Buffer
+	d := NewDaemon([]*Job{job}, nil, log.New(&logs, "", 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !strings.Contains(logs.String(), "Daemon stopped") {
+		t.Errorf("expected a stop line, got:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/config"
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/schedule"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Job is a scheduled playlist operation, ready to run
+type Job struct {
+	Name       string
+	Operation  string
+	PlaylistID spotify.ID
+	Schedule   *schedule.Schedule
+
+	cfg   config.JobConfig
+	steps []playlist.Step
+	chunk playlist.ChunkOptions
+	genre playlist.GenreFilter
+}
+
+// New validates a job definition and fills in options it leaves unset
+// from the configured defaults
+func New(cfg config.JobConfig, playlistID spotify.ID, defaults config.DefaultsConfig) (*Job, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, fmt.Errorf("job %q: %w", cfg.Name, err)
+	}
+
+	sched, err := schedule.Parse(cfg.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("job %q: %w", cfg.Name, err)
+	}
+
+	job := &Job{
+		Name:       cfg.Name,
+		Operation:  cfg.Operation,
+		PlaylistID: playlistID,
+		Schedule:   sched,
+		cfg:        cfg,
+	}
+
+	switch cfg.Operation {
+	case "sort":
+		if job.cfg.By == "" {
+			job.cfg.By = defaults.SortBy
+		}
+	case "fresh":
+		if job.cfg.Days == 0 {
+			job.cfg.Days = defaults.FreshDays
+		}
+	case "chunk":
+		if job.cfg.Size == 0 {
+			job.cfg.Size = defaults.ChunkSize
+		}
+		if job.cfg.Strategy == "duration" && job.cfg.Minutes == 0 {
+			job.cfg.Minutes = 60
+		}
+		job.chunk = playlist.ChunkOptions{
+			Strategy:     job.cfg.Strategy,
+			Size:         job.cfg.Size,
+			Duration:     time.Duration(job.cfg.Minutes) * time.Minute,
+			NameTemplate: job.cfg.NameTemplate,
+		}
+		if err := job.chunk.Check(); err != nil {
+			return nil, fmt.Errorf("job %q: %w", cfg.Name, err)
+		}
+	case "genre":
+		job.genre = playlist.GenreFilter{
+			Include: splitList(cfg.Genre),
+			Exclude: splitList(cfg.ExcludeGenre),
+			Match:   cfg.Match,
+		}
+		if err := job.genre.Check(); err != nil {
+			return nil, fmt.Errorf("job %q: %w", cfg.Name, err)
+		}
+	case "run":
+		job.steps, err = playlist.ParsePipelineText(cfg.Steps)
+		if err != nil {
+			return nil, fmt.Errorf("job %q: invalid steps: %w", cfg.Name, err)
+		}
+	}
+
+	return job, nil
+}
+
+// Describe returns the operation and its options, e.g. "fresh 30 days → Fresh Finds"
+func (j *Job) Describe() string {
+	c := j.cfg
+	switch c.Operation {
+	case "sort":
+		return "sort by " + c.By
+	case "remove-age":
+		return fmt.Sprintf("remove-age %d days", c.Days)
+	case "remove-artist":
+		return "remove-artist " + c.Artist
+	case "run":
+		return "run " + c.Steps
+	case "fresh":
+		if c.By == "released" {
+			return fmt.Sprintf("fresh released %d days → %s", c.Days, c.Target)
+		}
+		return fmt.Sprintf("fresh %d days → %s", c.Days, c.Target)
+	case "chunk":
+		switch c.Strategy {
+		case "", "random":
+			return fmt.Sprintf("chunk of %d → %s", c.Size, c.Target)
+		case "duration":
+			return fmt.Sprintf("chunk of %d minutes → %s", c.Minutes, c.Target)
+		default:
+			return fmt.Sprintf("chunk of %d by %s → %s", c.Size, c.Strategy, c.Target)
+		}
+	case "genre":
+		return fmt.Sprintf("genre %s → %s", j.genre.Describe(), c.Target)
+	default:
+		return c.Operation
+	}
+}
+
+// Run performs the job's operation with manager and returns a short
+// summary of what changed. A playlist given by name is looked up afresh on
+// every run, and playlists built by fresh, chunk and genre jobs are
+// overwritten every time.
+func (j *Job) Run(ctx context.Context, manager *playlist.Manager) (string, error) {
+	c := j.cfg
+	id, err := manager.ResolvePlaylistRef(ctx, string(j.PlaylistID))
+	if err != nil {
+		return "", err
+	}
+
+	switch c.Operation {
+	case "shuffle":
+		if err := manager.ShufflePlaylist(ctx, id); err != nil {
+			return "", fmt.Errorf("failed to shuffle playlist: %w", err)
+		}
+		return "playlist shuffled", nil
+
+	case "sort":
+		if err := manager.SortPlaylist(ctx, id, c.By); err != nil {
+			return "", fmt.Errorf("failed to sort playlist: %w", err)
+		}
+		return "playlist sorted by " + c.By, nil
+
+	case "reverse":
+		if err := manager.ReversePlaylist(ctx, id); err != nil {
+			return "", fmt.Errorf("failed to reverse playlist: %w", err)
+		}
+		return "playlist order reversed", nil
+
+	case "remove-age":
+		n, err := manager.RemoveOldTracks(ctx, id, c.Days)
+		if err != nil {
+			return "", fmt.Errorf("failed to remove old tracks: %w", err)
+		}
+		return fmt.Sprintf("removed %d tracks older than %d days", n, c.Days), nil
+
+	case "remove-artist":
+		n, err := manager.RemoveTracksByArtist(ctx, id, c.Artist)
+		if err != nil {
+			return "", fmt.Errorf("failed to remove tracks by artist: %w", err)
+		}
+		return fmt.Sprintf("removed %d tracks by %s", n, c.Artist), nil
+
+	case "run":
+		result, err := manager.RunPipeline(ctx, id, j.steps, false)
+		if err != nil {
+			return "", fmt.Errorf("pipeline failed: %w", err)
+		}
+		if !result.Changed {
+			return "pipeline made no changes", nil
+		}
+		return fmt.Sprintf("pipeline applied, %d → %d tracks", result.Before, result.After), nil
+
+	case "fresh":
+		n, err := manager.CreateFreshPlaylist(ctx, id, c.Target, playlist.FreshOptions{By: c.By, Days: c.Days}, true)
+		if err != nil {
+			return "", fmt.Errorf("failed to build fresh playlist: %w", err)
+		}
+		return fmt.Sprintf("'%s' rebuilt with %d tracks", c.Target, n), nil
+
+	case "chunk":
+		n, err := manager.CreateChunkPlaylists(ctx, id, c.Target, j.chunk, true)
+		if err != nil {
+			return "", fmt.Errorf("failed to build chunk playlists: %w", err)
+		}
+		return fmt.Sprintf("%d chunk playlists rebuilt", n), nil
+
+	case "genre":
+		n, err := manager.CreateGenrePlaylist(ctx, id, c.Target, j.genre, true)
+		if err != nil {
+			return "", fmt.Errorf("failed to build genre playlist: %w", err)
+		}
+		return fmt.Sprintf("'%s' rebuilt with %d tracks", c.Target, n), nil
+	}
+
+	return "", fmt.Errorf("unknown operation %q", c.Operation)
+}
+
+// splitList splits a comma-separated config value, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,153 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/petabloc/spotify-shuffle/internal/config"
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/zmb3/spotify/v2"
+)
+
+var testDefaults = config.DefaultsConfig{SortBy: "title", ChunkSize: 250, FreshDays: 30}
+
+// fakeSpotify serves a three-track playlist and records the URIs written
+// back to it
+type fakeSpotify struct {
+	mu      sync.Mutex
+	written []string
+	fail    bool
+}
+
+func newFakeSpotify(t *testing.T) (*fakeSpotify, *spotify.Client) {
+	t.Helper()
+	f := &fakeSpotify{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.fail {
+			http.Error(w, `{"error":{"status":500,"message":"boom"}}`, http.StatusInternalServerError)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"total":3,"items":[
+				{"track":{"id":"a","name":"Alpha","uri":"spotify:track:a"}},
+				{"track":{"id":"b","name":"Bravo","uri":"spotify:track:b"}},
+				{"track":{"id":"c","name":"Charlie","uri":"spotify:track:c"}}]}`)
+		case http.MethodPut:
+			f.written = strings.Split(r.URL.Query().Get("uris"), ",")
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"snapshot_id":"s"}`)
+		default:
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return f, spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+}
+
+func TestNew(t *testing.T) {
+	job, err := New(config.JobConfig{
+		Name: "nightly", Schedule: "@daily", Operation: "fresh", Playlist: "p", Target: "Fresh Finds",
+	}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, want := job.Describe(), "fresh 30 days → Fresh Finds"; got != want {
+		t.Errorf("Describe() = %q, want %q (days should default from config)", got, want)
+	}
+
+	job, err = New(config.JobConfig{
+		Name: "released", Schedule: "@daily", Operation: "fresh", Playlist: "p", Target: "New Releases", By: "released", Days: 14,
+	}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, want := job.Describe(), "fresh released 14 days → New Releases"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+
+	job, err = New(config.JobConfig{
+		Name: "loud", Schedule: "@daily", Operation: "genre", Playlist: "p", Target: "Loud",
+		Genre: "rock, punk", ExcludeGenre: "pop punk", Match: "exact",
+	}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, want := job.Describe(), "genre rock, punk (exact), not pop punk → Loud"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+
+	job, err = New(config.JobConfig{Name: "s", Schedule: "@daily", Operation: "sort", Playlist: "p"}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := job.Describe(); got != "sort by title" {
+		t.Errorf("Describe() = %q, want sort by title", got)
+	}
+
+	job, err = New(config.JobConfig{
+		Name: "hours", Schedule: "@daily", Operation: "chunk", Playlist: "p", Target: "Hour", Strategy: "duration",
+	}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := job.Describe(); got != "chunk of 60 minutes → Hour" {
+		t.Errorf("Describe() = %q, want 60-minute chunks by default", got)
+	}
+	// Quoted values in steps keep their spaces
+	if _, err := New(config.JobConfig{
+		Name: "clean", Schedule: "@daily", Operation: "run", Playlist: "p", Steps: `remove-where artist = "Daft Punk" / dedupe`,
+	}, "p", testDefaults); err != nil {
+		t.Errorf("New() with quoted steps error = %v", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []config.JobConfig{
+		{Name: "a", Schedule: "@sometimes", Operation: "shuffle", Playlist: "p"},
+		{Name: "b", Schedule: "@daily", Operation: "run", Playlist: "p", Steps: "dedupe / explode"},
+		{Name: "c", Schedule: "@daily", Operation: "chunk", Playlist: "p"},
+		{Name: "d", Schedule: "@daily", Operation: "chunk", Playlist: "p", Target: "T", Strategy: "colour"},
+		{Name: "e", Schedule: "@daily", Operation: "chunk", Playlist: "p", Target: "T", NameTemplate: "{base}-{week}"},
+	}
+
+	for _, cfg := range tests {
+		_, err := New(cfg, "p", testDefaults)
+		if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("job %q", cfg.Name)) {
+			t.Errorf("New(%s) error = %v, want error naming the job", cfg.Name, err)
+		}
+	}
+}
+
+func TestJobRun(t *testing.T) {
+	fake, client := newFakeSpotify(t)
+
+	job, err := New(config.JobConfig{
+		Name: "weekly", Schedule: "0 7 * * mon", Operation: "run", Playlist: "p", Steps: "reverse",
+	}, "p", testDefaults)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	summary, err := job.Run(context.Background(), playlist.NewManager(client))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary != "pipeline applied, 3 → 3 tracks" {
+		t.Errorf("Run() summary = %q", summary)
+	}
+	if got := strings.Join(fake.written, " "); got != "spotify:track:c spotify:track:b spotify:track:a" {
+		t.Errorf("written = %s, want reversed tracks", got)
+	}
+}
@@ -0,0 +1,158 @@
+package playlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// BackupVersion is the version of the backup format written by WriteBackup.
+// ReadBackup accepts this version and older ones.
+const BackupVersion = 1
+
+// Backup is an archive of playlists with their tracks in order
+type Backup struct {
+	Version   int              `json:"version"`
+	Created   time.Time        `json:"created"`
+	User      string           `json:"user,omitempty"`
+	Playlists []PlaylistBackup `json:"playlists"`
+}
+
+// PlaylistBackup is one playlist in a backup
+type PlaylistBackup struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	Public        bool          `json:"public"`
+	Collaborative bool          `json:"collaborative"`
+	SnapshotID    string        `json:"snapshot_id,omitempty"`
+	Tracks        []BackupTrack `json:"tracks"`
+}
+
+// BackupTrack is one playlist item in a backup
+type BackupTrack struct {
+	URI     string    `json:"uri"`
+	Name    string    `json:"name"`
+	Artists []string  `json:"artists,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by,omitempty"`
+}
+
+// RestoreResult describes a restored playlist
+type RestoreResult struct {
+	PlaylistID spotify.ID
+	Created    bool
+	Tracks     int
+}
+
+// WriteBackup writes b as indented JSON
+func WriteBackup(w io.Writer, b *Backup) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// ReadBackup reads a backup written by WriteBackup, rejecting versions newer
+// than this program understands
+func ReadBackup(r io.Reader) (*Backup, error) {
+	var b Backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+	if b.Version < 1 {
+		return nil, fmt.Errorf("invalid backup: missing format version")
+	}
+	if b.Version > BackupVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than this program supports (%d); please upgrade", b.Version, BackupVersion)
+	}
+	return &b, nil
+}
+
+// Find returns the playlists in the backup whose ID or name (ignoring
+// case) equals ref
+func (b *Backup) Find(ref string) []PlaylistBackup {
+	var found []PlaylistBackup
+	for _, p := range b.Playlists {
+		if p.ID == ref || strings.EqualFold(p.Name, ref) {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// BackupPlaylist downloads a playlist's tracks and returns it in backup
+// form. Local files are left out, as they are from every track listing,
+// since the Spotify API can't add them back. The API returns descriptions
+// HTML-escaped but takes them back as plain text, so they are stored
+// unescaped; otherwise every restore would escape them once more.
+func (m *Manager) BackupPlaylist(ctx context.Context, p spotify.SimplePlaylist) (PlaylistBackup, error) {
+	tracks, err := m.GetPlaylistTracks(ctx, p.ID)
+	if err != nil {
+		return PlaylistBackup{}, err
+	}
+
+	backup := PlaylistBackup{
+		ID:            string(p.ID),
+		Name:          p.Name,
+		Description:   html.UnescapeString(p.Description),
+		Public:        p.IsPublic,
+		Collaborative: p.Collaborative,
+		SnapshotID:    p.SnapshotID,
+		Tracks:        make([]BackupTrack, len(tracks)),
+	}
+	for i, t := range tracks {
+		backup.Tracks[i] = BackupTrack{
+			URI:     string(t.URI),
+			Name:    t.Name,
+			Artists: t.Artists,
+			AddedAt: t.AddedAt.UTC(),
+			AddedBy: t.AddedBy,
+		}
+	}
+	return backup, nil
+}
+
+// RestorePlaylist writes a backed up playlist to Spotify. With an empty
+// target a new playlist is created; otherwise target's details and tracks
+// are overwritten. Spotify sets added-at itself, so restored tracks are
+// dated now, and the collaborative flag can only be set on new playlists.
+func (m *Manager) RestorePlaylist(ctx context.Context, p PlaylistBackup, target spotify.ID) (RestoreResult, error) {
+	uris := make([]spotify.URI, len(p.Tracks))
+	for i, t := range p.Tracks {
+		uris[i] = spotify.URI(t.URI)
+	}
+
+	result := RestoreResult{PlaylistID: target, Tracks: len(uris)}
+
+	if target == "" {
+		id, err := m.createPlaylist(ctx, p.Name, p.Description, p.Public && !p.Collaborative, p.Collaborative)
+		if err != nil {
+			return result, err
+		}
+		result.PlaylistID = id
+		result.Created = true
+
+		if err := m.replacePlaylistTracks(ctx, id, uris); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+
+	current, err := m.GetPlaylistTracks(ctx, target)
+	if err != nil {
+		return result, err
+	}
+	if err := m.client.ChangePlaylistNameAccessAndDescription(ctx, target, p.Name, p.Description, p.Public); err != nil {
+		return result, fmt.Errorf("failed to update playlist details: %w", err)
+	}
+	if err := m.rewritePlaylistTracks(ctx, target, trackURIs(current), uris); err != nil {
+		return result, err
+	}
+	return result, nil
+}
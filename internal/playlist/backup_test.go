@@ -0,0 +1,194 @@
+package playlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestBackupRoundTrip(t *testing.T) {
+	added := time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)
+	backup := &Backup{
+		Version: BackupVersion,
+		Created: added,
+		User:    "alice",
+		Playlists: []PlaylistBackup{{
+			ID: "p1", Name: "Road Trip", Description: "Songs for the car", Public: true, Collaborative: false,
+			Tracks: []BackupTrack{{URI: "spotify:track:1", Name: "One", Artists: []string{"A"}, AddedAt: added, AddedBy: "bob"}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBackup(&buf, backup); err != nil {
+		t.Fatalf("WriteBackup() error = %v", err)
+	}
+	got, err := ReadBackup(&buf)
+	if err != nil {
+		t.Fatalf("ReadBackup() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, backup) {
+		t.Errorf("round trip = %+v, want %+v", got, backup)
+	}
+}
+
+func TestReadBackupVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"missing version", `{"playlists":[]}`, "missing format version"},
+		{"newer version", `{"version":99,"playlists":[]}`, "newer than this program supports"},
+		{"not json", `tracks.csv`, "invalid backup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadBackup(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadBackup() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackupFind(t *testing.T) {
+	backup := &Backup{Playlists: []PlaylistBackup{
+		{ID: "p1", Name: "Road Trip"},
+		{ID: "p2", Name: "road trip"},
+		{ID: "p3", Name: "Focus"},
+	}}
+
+	if got := backup.Find("ROAD TRIP"); len(got) != 2 {
+		t.Errorf("Find() by name = %d playlists, want 2", len(got))
+	}
+	if got := backup.Find("p3"); len(got) != 1 || got[0].Name != "Focus" {
+		t.Errorf("Find() by ID = %+v", got)
+	}
+	if got := backup.Find("Road"); len(got) != 0 {
+		t.Errorf("Find() should not match partial names, got %+v", got)
+	}
+}
+
+// restoreServer fakes the endpoints RestorePlaylist uses on top of the
+// playlist tracks API
+type restoreServer struct {
+	tracks  *fakePlaylist
+	mu      sync.Mutex
+	created map[string]interface{}
+	changed map[string]interface{}
+}
+
+func newRestoreServer(t *testing.T) (*restoreServer, *spotify.Client) {
+	t.Helper()
+	s := &restoreServer{tracks: &fakePlaylist{tracks: make(map[string][]string)}}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+}
+
+func (s *restoreServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/me":
+		w.Write([]byte(`{"id":"alice"}`))
+	case r.URL.Path == "/me/playlists":
+		w.Write([]byte(`{"items":[{"id":"p1","name":"Road Trip"}]}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/users/alice/playlists":
+		s.mu.Lock()
+		json.NewDecoder(r.Body).Decode(&s.created)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"new1"}`))
+	case r.Method == http.MethodPut && strings.Count(strings.Trim(r.URL.Path, "/"), "/") == 1:
+		s.mu.Lock()
+		json.NewDecoder(r.Body).Decode(&s.changed)
+		s.mu.Unlock()
+	default:
+		s.tracks.ServeHTTP(w, r)
+	}
+}
+
+func TestRestorePlaylistAsNew(t *testing.T) {
+	server, client := newRestoreServer(t)
+	m := NewManager(client)
+
+	p := PlaylistBackup{
+		ID: "p1", Name: "Team Mix", Description: "Shared", Public: true, Collaborative: true,
+		Tracks: []BackupTrack{{URI: "spotify:track:1"}, {URI: "spotify:track:2"}},
+	}
+
+	result, err := m.RestorePlaylist(context.Background(), p, "")
+	if err != nil {
+		t.Fatalf("RestorePlaylist() error = %v", err)
+	}
+
+	if !result.Created || result.PlaylistID != "new1" || result.Tracks != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if got := server.tracks.get("new1"); !reflect.DeepEqual(got, []string{"spotify:track:1", "spotify:track:2"}) {
+		t.Errorf("restored tracks = %v", got)
+	}
+	// Spotify rejects public collaborative playlists
+	if server.created["name"] != "Team Mix" || server.created["public"] != false || server.created["collaborative"] != true {
+		t.Errorf("created playlist with %v", server.created)
+	}
+}
+
+func TestRestorePlaylistOverwrite(t *testing.T) {
+	server, client := newRestoreServer(t)
+	server.tracks.set("p1", testURIs("old", 3))
+	m := NewManager(client)
+
+	p := PlaylistBackup{ID: "p1", Name: "Road Trip", Description: "Back again", Public: false, Tracks: []BackupTrack{{URI: "spotify:track:a"}}}
+
+	result, err := m.RestorePlaylist(context.Background(), p, "p1")
+	if err != nil {
+		t.Fatalf("RestorePlaylist() error = %v", err)
+	}
+
+	if result.Created || result.PlaylistID != "p1" || result.Tracks != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	if got := server.tracks.get("p1"); !reflect.DeepEqual(got, []string{"spotify:track:a"}) {
+		t.Errorf("tracks after overwrite = %v", got)
+	}
+	if server.created != nil {
+		t.Error("overwriting should not create a playlist")
+	}
+	if server.changed["name"] != "Road Trip" || server.changed["description"] != "Back again" || server.changed["public"] != false {
+		t.Errorf("changed details to %v", server.changed)
+	}
+}
+
+func TestBackupRestoreDescription(t *testing.T) {
+	server, client := newRestoreServer(t)
+	server.tracks.set("p1", testURIs("a", 2))
+	m := NewManager(client)
+
+	backup, err := m.BackupPlaylist(context.Background(), spotify.SimplePlaylist{
+		ID: "p1", Name: "Road Trip", Description: "Mum&#x27;s &amp; Dad&#x27;s picks",
+	})
+	if err != nil {
+		t.Fatalf("BackupPlaylist() error = %v", err)
+	}
+	if want := "Mum's & Dad's picks"; backup.Description != want {
+		t.Errorf("backed up description = %q, want %q", backup.Description, want)
+	}
+
+	if _, err := m.RestorePlaylist(context.Background(), backup, "p1"); err != nil {
+		t.Fatalf("RestorePlaylist() error = %v", err)
+	}
+	if got := server.changed["description"]; got != "Mum's & Dad's picks" {
+		t.Errorf("restored description = %v, want it unescaped", got)
+	}
+}
@@ -0,0 +1,95 @@
+package playlist
+
+import (
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Cache buckets used by the manager
+const (
+	bucketTracks  = "tracks"
+	bucketAlbums  = "albums"
+	bucketArtists = "artists"
+	bucketGenres  = "genres"
+
+	// bucketFeatures holds Spotify audio features keyed by track ID
+	bucketFeatures = "features"
+
+	// bucketPlaylists holds playlist contents keyed by playlist ID. Entries
+	// never expire; they are only used while the snapshot ID matches.
+	bucketPlaylists = "playlists"
+)
+
+// Track and album metadata practically never changes; artist genres are
+// re-tagged by Spotify from time to time, so they expire sooner
+const (
+	trackTTL  = 30 * 24 * time.Hour
+	artistTTL = 7 * 24 * time.Hour
+)
+
+// bucketMaxAge is how long entries are kept on disk: as long as lookups
+// use them, and cached playlists for as long as they are likely to be
+// downloaded again
+var bucketMaxAge = map[string]time.Duration{
+	bucketTracks:    trackTTL,
+	bucketAlbums:    trackTTL,
+	bucketFeatures:  trackTTL,
+	bucketArtists:   artistTTL,
+	bucketGenres:    artistTTL,
+	bucketPlaylists: 90 * 24 * time.Hour,
+}
+
+// cachedTrack is the cached form of a track's lookup data
+type cachedTrack struct {
+	ArtistIDs []spotify.ID `json:"artist_ids"`
+	AlbumID   spotify.ID   `json:"album_id,omitempty"`
+}
+
+// cachedAlbum is the cached form of an album
+type cachedAlbum struct {
+	Name        string `json:"name"`
+	ReleaseDate string `json:"release_date,omitempty"`
+}
+
+// cachedArtist is the cached form of an artist
+type cachedArtist struct {
+	Name   string   `json:"name"`
+	Genres []string `json:"genres"`
+}
+
+// playlistCacheVersion is bumped whenever Track gains fields, so playlists
+// cached by older versions are downloaded again
+const playlistCacheVersion = 2
+
+// cachedPlaylist is the cached form of a playlist's tracks at one snapshot
+type cachedPlaylist struct {
+	Version    int     `json:"version"`
+	SnapshotID string  `json:"snapshot_id"`
+	Tracks     []Track `json:"tracks"`
+}
+
+// cacheGet reads from the cache when one is configured
+func (m *Manager) cacheGet(bucket, key string, ttl time.Duration, v interface{}) bool {
+	if m.cache == nil {
+		return false
+	}
+	return m.cache.Get(bucket, key, ttl, v)
+}
+
+// cachePut writes to the cache when one is configured. Cache failures
+// never fail an operation.
+func (m *Manager) cachePut(bucket, key string, v interface{}) {
+	if m.cache == nil {
+		return
+	}
+	m.cache.Put(bucket, key, v)
+}
+
+// cacheDelete removes an entry when a cache is configured
+func (m *Manager) cacheDelete(bucket, key string) {
+	if m.cache == nil {
+		return
+	}
+	m.cache.Delete(bucket, key)
+}
@@ -0,0 +1,151 @@
+package playlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/cache"
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestGetTrackGenres_UsesCache(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tracks"):
+			w.Write([]byte(`{"tracks":[{"id":"t1","artists":[{"id":"a1","name":"Artist"}],"album":{"id":"al1","name":"Album"}}]}`))
+		case strings.HasSuffix(r.URL.Path, "/artists"):
+			w.Write([]byte(`{"artists":[{"id":"a1","name":"Artist","genres":["jazz","bebop"]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.Open() error = %v", err)
+	}
+
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+	manager.SetCache(c)
+
+	// Tracks cached by older versions carry no artist IDs and need a lookup
+	want := map[spotify.ID][]string{"t1": {"jazz", "bebop"}}
+	for i := 0; i < 2; i++ {
+		genres, err := manager.getTrackGenres(context.Background(), []Track{{ID: "t1"}})
+		if err != nil {
+			t.Fatalf("getTrackGenres() error = %v", err)
+		}
+		if !reflect.DeepEqual(genres, want) {
+			t.Errorf("getTrackGenres() = %v, want %v", genres, want)
+		}
+	}
+
+	// The second lookup must be served entirely from the cache
+	if requests["/tracks"] != 1 || requests["/artists"] != 1 {
+		t.Errorf("API requests = %v, want one tracks and one artists request", requests)
+	}
+}
+
+func TestGetTrackGenres_UsesArtistIDsFromPlaylist(t *testing.T) {
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/artists" {
+			w.Write([]byte(`{"artists":[{"id":"a1","name":"Artist","genres":["jazz"]}]}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+
+	genres, err := manager.getTrackGenres(context.Background(), []Track{{ID: "t1", ArtistIDs: []spotify.ID{"a1"}}})
+	if err != nil {
+		t.Fatalf("getTrackGenres() error = %v", err)
+	}
+	if want := map[spotify.ID][]string{"t1": {"jazz"}}; !reflect.DeepEqual(genres, want) {
+		t.Errorf("getTrackGenres() = %v, want %v", genres, want)
+	}
+	if requests["/tracks"] != 0 {
+		t.Errorf("GetTracks called %d times, want 0 when artist IDs are known", requests["/tracks"])
+	}
+}
+
+func TestGetPlaylistTracks_SnapshotCache(t *testing.T) {
+	snapshot := "snap1"
+	trackRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/playlists/p1":
+			w.Write([]byte(`{"id":"p1","snapshot_id":"` + snapshot + `"}`))
+		case "/playlists/p1/tracks":
+			trackRequests++
+			w.Write([]byte(`{"total":1,"items":[{"added_at":"2024-01-02T03:04:05Z","added_by":{"id":"user1"},"track":{"id":"t1","name":"Song","uri":"spotify:track:t1","duration_ms":185000,"popularity":42,"external_ids":{"isrc":"USRC17607839"},"album":{"id":"al1","name":"Album","release_date":"1999-05-01"},"artists":[{"id":"a1","name":"Artist"}]}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := cache.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("cache.Open() error = %v", err)
+	}
+
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+	manager.SetCache(c)
+
+	fetch := func() []Track {
+		t.Helper()
+		tracks, err := manager.GetPlaylistTracks(context.Background(), "p1")
+		if err != nil {
+			t.Fatalf("GetPlaylistTracks() error = %v", err)
+		}
+		return tracks
+	}
+
+	first := fetch()
+	second := fetch()
+	if trackRequests != 1 {
+		t.Errorf("track downloads = %d, want 1 while the snapshot is unchanged", trackRequests)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached tracks = %+v, want %+v", second, first)
+	}
+	want := Track{
+		ID:          "t1",
+		Name:        "Song",
+		Artists:     []string{"Artist"},
+		ArtistIDs:   []spotify.ID{"a1"},
+		Album:       "Album",
+		AlbumID:     "al1",
+		ReleaseDate: "1999-05-01",
+		Duration:    185 * time.Second,
+		Popularity:  42,
+		ISRC:        "USRC17607839",
+		URI:         "spotify:track:t1",
+		AddedAt:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		AddedBy:     "user1",
+	}
+	if len(second) != 1 || !reflect.DeepEqual(second[0], want) {
+		t.Errorf("cached tracks = %+v, want %+v", second, want)
+	}
+
+	snapshot = "snap2"
+	fetch()
+	if trackRequests != 2 {
+		t.Errorf("track downloads = %d, want 2 after the snapshot changed", trackRequests)
+	}
+}
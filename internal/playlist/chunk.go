@@ -0,0 +1,383 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+	"github.com/zmb3/spotify/v2"
+)
+
+// ChunkStrategies lists the ways CreateChunkPlaylists can split a playlist
+var ChunkStrategies = []string{"random", "order", "added", "genre", "artist", "duration"}
+
+// DefaultChunkNameTemplate names chunks base-00, base-01, ...
+const DefaultChunkNameTemplate = "{base}-{index}"
+
+// ChunkOptions controls how a playlist is split into chunks
+type ChunkOptions struct {
+	// Strategy is one of ChunkStrategies; empty means random
+	Strategy string
+	// Size is the most tracks per chunk. The artist strategy exceeds it
+	// for artists with more tracks, since an artist is never split.
+	Size int
+	// Duration is the most playing time per chunk, for the duration
+	// strategy
+	Duration time.Duration
+	// NameTemplate names each chunk; see ChunkName. Empty means
+	// DefaultChunkNameTemplate.
+	NameTemplate string
+}
+
+// Chunk is one planned chunk playlist
+type Chunk struct {
+	Name        string
+	Description string
+	// Label describes what the chunk holds, such as its genre or the
+	// range of artists; it is empty for random and order chunks
+	Label  string
+	Tracks []Track
+}
+
+// chunkPlaceholders are the names ChunkName replaces in a template
+var chunkPlaceholders = regexp.MustCompile(`\{[^{}]*\}`)
+
+// Check reports whether the options describe a valid split
+func (o ChunkOptions) Check() error {
+	switch o.strategy() {
+	case "random", "order", "added", "genre", "artist":
+		if o.Size <= 0 {
+			return fmt.Errorf("chunk size must be greater than 0")
+		}
+	case "duration":
+		if o.Duration < time.Minute {
+			return fmt.Errorf("chunk duration must be at least one minute")
+		}
+	default:
+		return fmt.Errorf("unknown chunk strategy %q (available: %s)", o.Strategy, strings.Join(ChunkStrategies, ", "))
+	}
+
+	return CheckChunkNameTemplate(o.nameTemplate())
+}
+
+// CheckChunkNameTemplate reports placeholders ChunkName doesn't know
+func CheckChunkNameTemplate(template string) error {
+	for _, p := range chunkPlaceholders.FindAllString(template, -1) {
+		switch p {
+		case "{base}", "{index}", "{n}", "{total}", "{label}", "{date}":
+		default:
+			return fmt.Errorf("unknown placeholder %s in name template (use {base}, {index}, {n}, {total}, {label} or {date})", p)
+		}
+	}
+	return nil
+}
+
+func (o ChunkOptions) strategy() string {
+	if o.Strategy == "" {
+		return "random"
+	}
+	return o.Strategy
+}
+
+func (o ChunkOptions) nameTemplate() string {
+	if o.NameTemplate == "" {
+		return DefaultChunkNameTemplate
+	}
+	return o.NameTemplate
+}
+
+// ChunkName fills in a chunk name template. {base} is the base name,
+// {index} the zero-padded chunk number from 00, {n} the chunk number from
+// 1, {total} the number of chunks, {label} what the chunk holds (its
+// genre, for example) and {date} today's date.
+func ChunkName(template, base string, index, total int, label string, date time.Time) string {
+	return strings.NewReplacer(
+		"{base}", base,
+		"{index}", fmt.Sprintf("%02d", index),
+		"{n}", strconv.Itoa(index+1),
+		"{total}", strconv.Itoa(total),
+		"{label}", label,
+		"{date}", date.Format("2006-01-02"),
+	).Replace(template)
+}
+
+// PlanChunks splits tracks into chunks according to opts. trackGenres is
+// only used by the genre strategy. Names and descriptions are left empty.
+func PlanChunks(tracks []Track, opts ChunkOptions, trackGenres map[spotify.ID][]string) ([]Chunk, error) {
+	if err := opts.Check(); err != nil {
+		return nil, err
+	}
+
+	switch opts.strategy() {
+	case "random":
+		shuffled := append([]Track(nil), tracks...)
+		rand.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+		return splitBySize(shuffled, opts.Size, nil), nil
+
+	case "order":
+		return splitBySize(tracks, opts.Size, nil), nil
+
+	case "added":
+		sorted := append([]Track(nil), tracks...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].AddedAt.Before(sorted[j].AddedAt)
+		})
+		return splitBySize(sorted, opts.Size, addedLabel), nil
+
+	case "genre":
+		return chunksByGenre(tracks, opts.Size, trackGenres), nil
+
+	case "artist":
+		return chunksByArtist(tracks, opts.Size), nil
+
+	default: // duration
+		return chunksByDuration(tracks, opts.Duration), nil
+	}
+}
+
+// splitBySize cuts tracks into chunks of size, labelling each with label
+// when it is not nil
+func splitBySize(tracks []Track, size int, label func([]Track) string) []Chunk {
+	var chunks []Chunk
+	for start := 0; start < len(tracks); start += size {
+		end := min(start+size, len(tracks))
+		chunk := Chunk{Tracks: tracks[start:end]}
+		if label != nil {
+			chunk.Label = label(chunk.Tracks)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// addedLabel describes the months in which tracks sorted by added date
+// were added, e.g. "2023-01" or "2023-01 to 2023-04"
+func addedLabel(tracks []Track) string {
+	first := tracks[0].AddedAt.Format("2006-01")
+	last := tracks[len(tracks)-1].AddedAt.Format("2006-01")
+	if first == last {
+		return first
+	}
+	return first + " to " + last
+}
+
+// chunksByGenre puts each track in the chunk of its most common genre in
+// the playlist, so tracks gather in the larger genres rather than scatter
+// over micro-genres. Genres with more tracks than size take several
+// chunks; tracks without a genre go last.
+func chunksByGenre(tracks []Track, size int, trackGenres map[spotify.ID][]string) []Chunk {
+	counts := make(map[string]int)
+	for _, track := range tracks {
+		for _, genre := range trackGenres[track.ID] {
+			counts[genre]++
+		}
+	}
+
+	groups := make(map[string][]Track)
+	for _, track := range tracks {
+		best := ""
+		for _, genre := range trackGenres[track.ID] {
+			if best == "" || counts[genre] > counts[best] || counts[genre] == counts[best] && genre < best {
+				best = genre
+			}
+		}
+		groups[best] = append(groups[best], track)
+	}
+
+	// Largest genres first, ties by name, unknown last
+	var genres []string
+	for genre := range groups {
+		if genre != "" {
+			genres = append(genres, genre)
+		}
+	}
+	sort.Slice(genres, func(i, j int) bool {
+		if len(groups[genres[i]]) != len(groups[genres[j]]) {
+			return len(groups[genres[i]]) > len(groups[genres[j]])
+		}
+		return genres[i] < genres[j]
+	})
+	if _, ok := groups[""]; ok {
+		genres = append(genres, "")
+	}
+
+	var chunks []Chunk
+	for _, genre := range genres {
+		label := genre
+		if label == "" {
+			label = "unknown genre"
+		}
+		chunks = append(chunks, splitBySize(groups[genre], size, func([]Track) string { return label })...)
+	}
+	return chunks
+}
+
+// chunksByArtist packs tracks grouped by their first artist into chunks of
+// at most size tracks, in artist order. An artist is never split across
+// chunks, so one with more than size tracks gets a chunk of its own.
+func chunksByArtist(tracks []Track, size int) []Chunk {
+	groups := make(map[string][]Track)
+	names := make(map[string]string)
+	for _, track := range tracks {
+		name := ""
+		if len(track.Artists) > 0 {
+			name = track.Artists[0]
+		}
+		key := strings.ToLower(name)
+		if _, ok := groups[key]; !ok {
+			names[key] = name
+		}
+		groups[key] = append(groups[key], track)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var chunks []Chunk
+	var current []Track
+	var first, last string
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		label := first
+		if last != first {
+			label = first + " to " + last
+		}
+		chunks = append(chunks, Chunk{Label: label, Tracks: current})
+		current = nil
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		if len(current) > 0 && len(current)+len(group) > size {
+			flush()
+		}
+		if len(current) == 0 {
+			first = names[key]
+		}
+		last = names[key]
+		current = append(current, group...)
+	}
+	flush()
+	return chunks
+}
+
+// chunksByDuration cuts tracks, in their current order, into chunks that
+// play for at most limit. A single track longer than limit gets a chunk of
+// its own.
+func chunksByDuration(tracks []Track, limit time.Duration) []Chunk {
+	var chunks []Chunk
+	var current []Track
+	var total time.Duration
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, Chunk{Label: progress.FormatDuration(total), Tracks: current})
+		}
+		current, total = nil, 0
+	}
+
+	for _, track := range tracks {
+		if len(current) > 0 && total+track.Duration > limit {
+			flush()
+		}
+		current = append(current, track)
+		total += track.Duration
+	}
+	flush()
+	return chunks
+}
+
+// PlanChunkPlaylists splits a playlist into named chunks without creating
+// anything, so the names can be checked first
+func (m *Manager) PlanChunkPlaylists(ctx context.Context, sourcePlaylistID spotify.ID, baseName string, opts ChunkOptions) ([]Chunk, error) {
+	if err := opts.Check(); err != nil {
+		return nil, err
+	}
+
+	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("source playlist is empty")
+	}
+
+	sourceName, err := m.PlaylistName(ctx, sourcePlaylistID)
+	if err != nil {
+		return nil, err
+	}
+
+	var trackGenres map[spotify.ID][]string
+	if opts.strategy() == "genre" {
+		if trackGenres, err = m.getTrackGenres(ctx, tracks); err != nil {
+			return nil, err
+		}
+	}
+
+	chunks, err := PlanChunks(tracks, opts, trackGenres)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now()
+	for i := range chunks {
+		c := &chunks[i]
+		c.Name = ChunkName(opts.nameTemplate(), baseName, i, len(chunks), c.Label, today)
+		c.Description = fmt.Sprintf("Chunk %d of %d from '%s' (%s), created %s",
+			i+1, len(chunks), sourceName, chunkStrategyDescription(opts), today.Format("2006-01-02"))
+		if c.Label != "" {
+			c.Description += ": " + c.Label
+		}
+	}
+	if err := checkChunkNames(chunks); err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// checkChunkNames reports two chunks given the same name, since the later
+// one would be skipped or would overwrite the earlier. Labels repeat when a
+// genre spans several chunks and are empty for random and order chunks, so
+// a template without {index} or {n} can collide. Names are compared
+// ignoring case, as FindPlaylistByName does.
+func checkChunkNames(chunks []Chunk) error {
+	seen := make(map[string]int, len(chunks))
+	for i, c := range chunks {
+		key := strings.ToLower(c.Name)
+		if j, ok := seen[key]; ok {
+			return fmt.Errorf("chunks %d and %d would both be named '%s' (add {index} or {n} to the name template)", j+1, i+1, c.Name)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// chunkStrategyDescription says how chunks were split, for descriptions
+func chunkStrategyDescription(opts ChunkOptions) string {
+	switch opts.strategy() {
+	case "order":
+		return "in playlist order"
+	case "added":
+		return "by date added"
+	case "genre":
+		return "by genre"
+	case "artist":
+		return "by artist"
+	case "duration":
+		return fmt.Sprintf("%d-minute chunks", int(opts.Duration.Minutes()))
+	default:
+		return "shuffled"
+	}
+}
@@ -0,0 +1,210 @@
+package playlist
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// chunkIDs returns the track IDs of each chunk, joined by spaces
+func chunkIDs(chunks []Chunk) []string {
+	var out []string
+	for _, c := range chunks {
+		out = append(out, strings.Join(trackIDs(c.Tracks), " "))
+	}
+	return out
+}
+
+func chunkLabels(chunks []Chunk) []string {
+	var out []string
+	for _, c := range chunks {
+		out = append(out, c.Label)
+	}
+	return out
+}
+
+func TestPlanChunks_Order(t *testing.T) {
+	tracks := []Track{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Strategy: "order", Size: 2}, nil)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+	if got, want := chunkIDs(chunks), []string{"1 2", "3 4", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %v, want %v", got, want)
+	}
+}
+
+func TestPlanChunks_Random(t *testing.T) {
+	var tracks []Track
+	for _, id := range strings.Fields("a b c d e f g") {
+		tracks = append(tracks, Track{ID: spotify.ID(id)})
+	}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Size: 3}, nil)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+
+	var all []string
+	for _, ids := range chunkIDs(chunks) {
+		all = append(all, strings.Fields(ids)...)
+	}
+	sort.Strings(all)
+	if len(chunks) != 3 || strings.Join(all, "") != "abcdefg" {
+		t.Errorf("random chunks = %v, want every track once in 3 chunks", chunkIDs(chunks))
+	}
+}
+
+func TestPlanChunks_Added(t *testing.T) {
+	at := func(month int) time.Time { return time.Date(2023, time.Month(month), 1, 0, 0, 0, 0, time.UTC) }
+	tracks := []Track{{ID: "c", AddedAt: at(5)}, {ID: "a", AddedAt: at(1)}, {ID: "b", AddedAt: at(2)}}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Strategy: "added", Size: 2}, nil)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+	if got, want := chunkIDs(chunks), []string{"a b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %v, want %v", got, want)
+	}
+	if got, want := chunkLabels(chunks), []string{"2023-01 to 2023-02", "2023-05"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+}
+
+func TestPlanChunks_Genre(t *testing.T) {
+	tracks := []Track{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}}
+	genres := map[spotify.ID][]string{
+		"1": {"rock", "indie rock"},
+		"2": {"rock"},
+		"3": {"indie rock", "rock"},
+		"4": {"jazz"},
+	}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Strategy: "genre", Size: 2}, genres)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+
+	// Every rock track goes to rock, the most common genre, which needs
+	// two chunks; tracks without genres come last
+	if got, want := chunkIDs(chunks), []string{"1 2", "3", "4", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %v, want %v", got, want)
+	}
+	if got, want := chunkLabels(chunks), []string{"rock", "rock", "jazz", "unknown genre"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+}
+
+func TestCheckChunkNames_GenreOverflow(t *testing.T) {
+	tracks := []Track{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}
+	genres := map[spotify.ID][]string{"1": {"rock"}, "2": {"rock"}, "3": {"rock"}, "4": {"jazz"}}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Strategy: "genre", Size: 2}, genres)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// rock needs two chunks, so naming by label alone gives "Big - rock" twice
+	for i := range chunks {
+		chunks[i].Name = ChunkName("{base} - {label}", "Big", i, len(chunks), chunks[i].Label, date)
+	}
+	if err := checkChunkNames(chunks); err == nil || !strings.Contains(err.Error(), "'Big - rock'") {
+		t.Errorf("checkChunkNames() error = %v, want a clash on 'Big - rock'", err)
+	}
+
+	for i := range chunks {
+		chunks[i].Name = ChunkName("{base} - {label} {n}", "Big", i, len(chunks), chunks[i].Label, date)
+	}
+	if err := checkChunkNames(chunks); err != nil {
+		t.Errorf("checkChunkNames() with {n} error = %v", err)
+	}
+}
+
+func TestPlanChunks_Artist(t *testing.T) {
+	tracks := []Track{
+		{ID: "b1", Artists: []string{"Blur"}},
+		{ID: "a1", Artists: []string{"ABBA"}},
+		{ID: "c1", Artists: []string{"Cream"}},
+		{ID: "b2", Artists: []string{"blur"}},
+		{ID: "b3", Artists: []string{"Blur", "Someone"}},
+		{ID: "d1", Artists: []string{"Doves"}},
+	}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Strategy: "artist", Size: 2}, nil)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+
+	// Blur's three tracks exceed the size but stay together
+	if got, want := chunkIDs(chunks), []string{"a1", "b1 b2 b3", "c1 d1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %v, want %v", got, want)
+	}
+	if got, want := chunkLabels(chunks), []string{"ABBA", "Blur", "Cream to Doves"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+}
+
+func TestPlanChunks_Duration(t *testing.T) {
+	tracks := []Track{
+		{ID: "1", Duration: 25 * time.Minute},
+		{ID: "2", Duration: 30 * time.Minute},
+		{ID: "3", Duration: 10 * time.Minute},
+		{ID: "4", Duration: 70 * time.Minute},
+		{ID: "5", Duration: 5 * time.Minute},
+	}
+
+	chunks, err := PlanChunks(tracks, ChunkOptions{Strategy: "duration", Duration: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("PlanChunks() error = %v", err)
+	}
+	if got, want := chunkIDs(chunks), []string{"1 2", "3", "4", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %v, want %v", got, want)
+	}
+	if chunks[0].Label != "55:00" {
+		t.Errorf("first label = %q, want the chunk's length", chunks[0].Label)
+	}
+}
+
+func TestChunkOptionsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ChunkOptions
+		wantErr string
+	}{
+		{"default", ChunkOptions{Size: 10}, ""},
+		{"no size", ChunkOptions{Strategy: "order"}, "size"},
+		{"no duration", ChunkOptions{Strategy: "duration", Size: 10}, "duration"},
+		{"unknown strategy", ChunkOptions{Strategy: "mood", Size: 10}, "unknown chunk strategy"},
+		{"unknown placeholder", ChunkOptions{Size: 10, NameTemplate: "{base} {week}"}, "{week}"},
+		{"template", ChunkOptions{Size: 10, NameTemplate: "{base} {n}/{total} {label} {date} {index}"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Check()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Check() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Check() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChunkName(t *testing.T) {
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := ChunkName(DefaultChunkNameTemplate, "Big", 3, 12, "", date); got != "Big-03" {
+		t.Errorf("default name = %q, want Big-03", got)
+	}
+	if got := ChunkName("{base} {n}/{total} - {label} ({date})", "Big", 0, 2, "rock", date); got != "Big 1/2 - rock (2024-05-01)" {
+		t.Errorf("templated name = %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+	"github.com/zmb3/spotify/v2"
+)
+
+// DurationSelections lists the ways SelectByDuration can pick tracks
+var DurationSelections = []string{"random", "popular", "title", "artist", "added"}
+
+// DurationSelection is a set of tracks picked to fill a target duration
+type DurationSelection struct {
+	Tracks []Track
+	Total  time.Duration
+	Target time.Duration
+	// Within reports whether Total is within the tolerance of Target
+	Within bool
+}
+
+// SelectByDuration picks tracks whose total duration is as close as
+// possible to target, and within tolerance of it if that can be done.
+// Tracks are considered in the order given by by: random, popular (most
+// popular first) or a sort key (title, artist, added). Duplicates and
+// tracks without a duration are left out.
+func SelectByDuration(tracks []Track, target, tolerance time.Duration, by string) (*DurationSelection, error) {
+	if target <= 0 {
+		return nil, fmt.Errorf("target duration must be greater than 0")
+	}
+	if tolerance < 0 {
+		return nil, fmt.Errorf("tolerance must not be negative")
+	}
+
+	candidates := keepTracks(dedupeTracks(tracks), func(t Track) bool { return t.Duration > 0 })
+	switch by {
+	case "random":
+		rand.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+	case "popular":
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Popularity > candidates[j].Popularity
+		})
+	default:
+		less, err := trackLess(by)
+		if err != nil {
+			return nil, fmt.Errorf("unknown selection %q (use random, popular, title, artist or added)", by)
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return less(candidates[i], candidates[j])
+		})
+	}
+
+	low, high := target-tolerance, target+tolerance
+
+	// Take tracks in order while they fit under the upper bound
+	var picked, rest []Track
+	var total time.Duration
+	for _, t := range candidates {
+		if total < low && total+t.Duration <= high {
+			picked = append(picked, t)
+			total += t.Duration
+			continue
+		}
+		rest = append(rest, t)
+	}
+
+	// Still short: every track left over is too long to add, but one may
+	// replace a picked track to close the gap, latest picks first
+	if total < low {
+		for p := len(picked) - 1; p >= 0; p-- {
+			d := picked[p].Duration
+			if i := findDuration(rest, low-total+d, high-total+d); i >= 0 {
+				total += rest[i].Duration - d
+				picked[p] = rest[i]
+				break
+			}
+		}
+	}
+
+	return &DurationSelection{
+		Tracks: picked,
+		Total:  total,
+		Target: target,
+		Within: total >= low && total <= high,
+	}, nil
+}
+
+// findDuration returns the index of the first track lasting between low
+// and high, or -1
+func findDuration(tracks []Track, low, high time.Duration) int {
+	for i, t := range tracks {
+		if t.Duration >= low && t.Duration <= high {
+			return i
+		}
+	}
+	return -1
+}
+
+// CreateDurationPlaylist creates a playlist from the source's tracks that
+// lasts about target; see SelectByDuration. Falling short of the tolerance
+// is not an error; check the selection's Within.
+func (m *Manager) CreateDurationPlaylist(ctx context.Context, sourcePlaylistID spotify.ID, name string, target, tolerance time.Duration, by string, overwrite bool) (*DurationSelection, error) {
+	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := SelectByDuration(tracks, target, tolerance, by)
+	if err != nil {
+		return nil, err
+	}
+	if len(selection.Tracks) == 0 {
+		return nil, fmt.Errorf("no tracks fit within %s", progress.FormatDuration(target+tolerance))
+	}
+
+	description := fmt.Sprintf("%s of tracks picked by %s", progress.FormatDuration(selection.Total), by)
+	if err := m.writeNamedPlaylist(ctx, name, description, trackURIs(selection.Tracks), overwrite); err != nil {
+		return nil, err
+	}
+
+	return selection, nil
+}
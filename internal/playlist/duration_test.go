@@ -0,0 +1,102 @@
+package playlist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func minutesTrack(id string, minutes int) Track {
+	return Track{ID: spotify.ID(id), URI: spotify.URI("spotify:track:" + id), Duration: time.Duration(minutes) * time.Minute}
+}
+
+func TestSelectByDuration_Random(t *testing.T) {
+	var tracks []Track
+	for i := 0; i < 40; i++ {
+		tracks = append(tracks, minutesTrack(string(rune('A'+i)), 3+i%3))
+	}
+
+	for run := 0; run < 20; run++ {
+		s, err := SelectByDuration(tracks, 60*time.Minute, 2*time.Minute, "random")
+		if err != nil {
+			t.Fatalf("SelectByDuration() error = %v", err)
+		}
+		if !s.Within || s.Total < 58*time.Minute || s.Total > 62*time.Minute {
+			t.Fatalf("total = %v, within = %v, want 58m-62m", s.Total, s.Within)
+		}
+	}
+}
+
+func TestSelectByDuration_Popular(t *testing.T) {
+	tracks := []Track{minutesTrack("a", 4), minutesTrack("b", 4), minutesTrack("c", 4)}
+	tracks[0].Popularity = 10
+	tracks[1].Popularity = 90
+	tracks[2].Popularity = 50
+
+	s, err := SelectByDuration(tracks, 8*time.Minute, 0, "popular")
+	if err != nil {
+		t.Fatalf("SelectByDuration() error = %v", err)
+	}
+	if got, want := trackIDs(s.Tracks), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tracks = %v, want %v", got, want)
+	}
+}
+
+func TestSelectByDuration_SkipsLongTracks(t *testing.T) {
+	// In title order a (5) and b (5) make 10; c (8) overshoots, d (2) fits
+	tracks := []Track{minutesTrack("a", 5), minutesTrack("b", 5), minutesTrack("c", 8), minutesTrack("d", 2)}
+	for i := range tracks {
+		tracks[i].Name = string(tracks[i].ID)
+	}
+
+	s, err := SelectByDuration(tracks, 12*time.Minute, 0, "title")
+	if err != nil {
+		t.Fatalf("SelectByDuration() error = %v", err)
+	}
+	if got := trackIDs(s.Tracks); !reflect.DeepEqual(got, []string{"a", "b", "d"}) || s.Total != 12*time.Minute || !s.Within {
+		t.Errorf("tracks = %v, total = %v, within = %v; want [a b d], 12m, true", got, s.Total, s.Within)
+	}
+}
+
+func TestSelectByDuration_Replaces(t *testing.T) {
+	// a (5) and b (5) make 10 of 13; nothing lasts 3, but swapping b for c (8) does
+	tracks := []Track{minutesTrack("a", 5), minutesTrack("b", 5), minutesTrack("c", 8), minutesTrack("d", 9)}
+	for i := range tracks {
+		tracks[i].Name = string(tracks[i].ID)
+	}
+
+	s, err := SelectByDuration(tracks, 13*time.Minute, 0, "title")
+	if err != nil {
+		t.Fatalf("SelectByDuration() error = %v", err)
+	}
+	if got := trackIDs(s.Tracks); !reflect.DeepEqual(got, []string{"a", "c"}) || !s.Within {
+		t.Errorf("tracks = %v, within = %v; want [a c], true", got, s.Within)
+	}
+}
+
+func TestSelectByDuration_Short(t *testing.T) {
+	tracks := []Track{minutesTrack("a", 3), minutesTrack("a", 3), minutesTrack("b", 0)}
+
+	s, err := SelectByDuration(tracks, 60*time.Minute, time.Minute, "random")
+	if err != nil {
+		t.Fatalf("SelectByDuration() error = %v", err)
+	}
+	if s.Within || len(s.Tracks) != 1 || s.Total != 3*time.Minute {
+		t.Errorf("got %d tracks, total %v, within %v; want 1, 3m, false", len(s.Tracks), s.Total, s.Within)
+	}
+}
+
+func TestSelectByDuration_Invalid(t *testing.T) {
+	tracks := []Track{minutesTrack("a", 3)}
+	if _, err := SelectByDuration(tracks, 0, 0, "random"); err == nil {
+		t.Error("expected error for zero target")
+	}
+	if _, err := SelectByDuration(tracks, time.Hour, -time.Minute, "random"); err == nil {
+		t.Error("expected error for negative tolerance")
+	}
+	if _, err := SelectByDuration(tracks, time.Hour, 0, "loudest"); err == nil {
+		t.Error("expected error for unknown selection")
+	}
+}
@@ -0,0 +1,63 @@
+package playlist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// InterruptedWriteError reports a playlist write that was cancelled partway
+// through and describes what the playlist holds as a result
+type InterruptedWriteError struct {
+	PlaylistID  spotify.ID
+	Written     int
+	Total       int
+	RolledBack  bool
+	RollbackErr error
+	Cause       error
+}
+
+func (e *InterruptedWriteError) Error() string {
+	switch {
+	case e.RolledBack:
+		return fmt.Sprintf("write interrupted (%v); playlist %s was restored to its previous contents", e.Cause, e.PlaylistID)
+	case e.RollbackErr != nil:
+		return fmt.Sprintf("write interrupted (%v) and restoring the previous contents failed (%v); playlist %s now holds only the first %d of %d tracks",
+			e.Cause, e.RollbackErr, e.PlaylistID, e.Written, e.Total)
+	default:
+		return fmt.Sprintf("write interrupted (%v); playlist %s now holds only the first %d of %d tracks", e.Cause, e.PlaylistID, e.Written, e.Total)
+	}
+}
+
+func (e *InterruptedWriteError) Unwrap() error {
+	return e.Cause
+}
+
+// AmbiguousPlaylistError reports a playlist name that matches more than one
+// playlist, or matches one too loosely to be trusted
+type AmbiguousPlaylistError struct {
+	Name    string
+	Matches []PlaylistMatch
+}
+
+func (e *AmbiguousPlaylistError) Error() string {
+	const shown = 5
+
+	if len(e.Matches) == 1 {
+		match := e.Matches[0]
+		return fmt.Sprintf("playlist name %q only loosely matches %q (%s); use a more specific name or the playlist ID",
+			e.Name, match.Playlist.Name, match.Playlist.ID)
+	}
+
+	var names []string
+	for i, match := range e.Matches {
+		if i == shown {
+			names = append(names, fmt.Sprintf("and %d more", len(e.Matches)-shown))
+			break
+		}
+		names = append(names, fmt.Sprintf("%q (%s)", match.Playlist.Name, match.Playlist.ID))
+	}
+	return fmt.Sprintf("playlist name %q is ambiguous, it matches %d playlists: %s; use a more specific name or the playlist ID",
+		e.Name, len(e.Matches), strings.Join(names, ", "))
+}
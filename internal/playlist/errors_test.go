@@ -0,0 +1,104 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func uriStrings(uris []spotify.URI) []string {
+	out := make([]string, len(uris))
+	for i, uri := range uris {
+		out[i] = string(uri)
+	}
+	return out
+}
+
+func TestReplacePlaylistTracks_Interrupted(t *testing.T) {
+	fake, client := newFakePlaylist(t)
+	manager := NewManager(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake.onWrite = func(writes int) {
+		if writes == 2 {
+			cancel()
+		}
+	}
+
+	err := manager.replacePlaylistTracks(ctx, "p1", testURIs("new", 350))
+
+	var interrupted *InterruptedWriteError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("replacePlaylistTracks() error = %v, want *InterruptedWriteError", err)
+	}
+	if interrupted.Written != 200 || interrupted.Total != 350 || interrupted.RolledBack {
+		t.Errorf("error = %+v, want 200 of 350 written and no rollback", interrupted)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Error("error should wrap context.Canceled")
+	}
+	if !strings.Contains(err.Error(), "first 200 of 350 tracks") {
+		t.Errorf("error message %q should describe the playlist state", err)
+	}
+	if got := fake.get("p1"); len(got) != 200 {
+		t.Errorf("playlist holds %d tracks, want 200", len(got))
+	}
+}
+
+func TestReplacePlaylistTracks_CancelledBeforeWrite(t *testing.T) {
+	fake, client := newFakePlaylist(t)
+	manager := NewManager(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := manager.replacePlaylistTracks(ctx, "p1", testURIs("new", 10))
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "unchanged") {
+		t.Errorf("replacePlaylistTracks() error = %v, want unchanged playlist", err)
+	}
+	if fake.writes != 0 {
+		t.Errorf("made %d writes, want none", fake.writes)
+	}
+}
+
+func TestRewritePlaylistTracks_RollsBack(t *testing.T) {
+	fake, client := newFakePlaylist(t)
+	manager := NewManager(client)
+
+	original := testURIs("old", 250)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake.onWrite = func(writes int) {
+		if writes == 1 {
+			cancel()
+		}
+	}
+
+	err := manager.rewritePlaylistTracks(ctx, "p1", original, testURIs("new", 250))
+
+	var interrupted *InterruptedWriteError
+	if !errors.As(err, &interrupted) || !interrupted.RolledBack {
+		t.Fatalf("rewritePlaylistTracks() error = %v, want rolled back *InterruptedWriteError", err)
+	}
+	if got := fake.get("p1"); !reflect.DeepEqual(got, uriStrings(original)) {
+		t.Errorf("playlist after rollback has %d tracks, want the original %d in order", len(got), len(original))
+	}
+}
+
+func TestRewritePlaylistTracks_Completes(t *testing.T) {
+	fake, client := newFakePlaylist(t)
+	manager := NewManager(client)
+
+	uris := testURIs("new", 150)
+	if err := manager.rewritePlaylistTracks(context.Background(), "p1", testURIs("old", 150), uris); err != nil {
+		t.Fatalf("rewritePlaylistTracks() error = %v", err)
+	}
+	if got := fake.get("p1"); !reflect.DeepEqual(got, uriStrings(uris)) {
+		t.Errorf("playlist = %v, want %v", got, uris)
+	}
+}
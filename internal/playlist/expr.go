@@ -0,0 +1,506 @@
+package playlist
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ExprFields lists the track fields a filter expression can test
+var ExprFields = []string{
+	"name", "artist", "album", "added_by", "isrc", "uri", "id",
+	"popularity", "year", "duration", "age",
+	"added_at", "release_date", "explicit",
+}
+
+// Expr is a parsed filter expression over track fields, such as
+//
+//	artist =~ "Foo" or (added_by = "bob" and added_at < 2025-01-01) or duration > 10m or explicit
+//
+// Comparisons are field OP value, with OP one of = != < <= > >= =~ !~, and
+// combine with and, or, not and parentheses. Text compares ignoring case;
+// =~ and !~ match a regular expression. A track matches artist = "x" when
+// any of its artists does, and artist != "x" when none does. Dates are
+// YYYY-MM-DD, YYYY-MM or YYYY and compare by day; durations are Go
+// durations (3m30s), m:ss, or days and weeks (90d, 2w). age is the time
+// since a track was added. A boolean field alone, like explicit, tests
+// that it is true. Tracks without a value for a field never match a
+// comparison on it.
+type Expr struct {
+	src  string
+	root exprNode
+}
+
+// exprNode is one node of a parsed expression, evaluated at a fixed time
+// so age is stable across a playlist
+type exprNode func(t Track, now time.Time) bool
+
+// ParseExpr parses a filter expression
+func ParseExpr(src string) (*Expr, error) {
+	tokens, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the expression as it was written
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Match reports whether a track satisfies the expression
+func (e *Expr) Match(t Track) bool {
+	return e.root(t, time.Now())
+}
+
+// FilterTracks returns the tracks matching e, measuring age from now
+func FilterTracks(tracks []Track, e *Expr, now time.Time) []Track {
+	return keepTracks(tracks, func(t Track) bool { return e.root(t, now) })
+}
+
+// exprToken is a lexed word, operator, parenthesis or quoted string
+type exprToken struct {
+	text   string
+	pos    int
+	quoted bool
+}
+
+// lexExpr splits an expression into tokens
+func lexExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	runes := []rune(src)
+	isOp := func(r rune) bool { return strings.ContainsRune("=!<>~", r) }
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(' || r == ')':
+			tokens = append(tokens, exprToken{text: string(r), pos: i})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+			i++
+			tokens = append(tokens, exprToken{text: b.String(), pos: start, quoted: true})
+
+		case isOp(r):
+			start := i
+			for i < len(runes) && isOp(runes[i]) {
+				i++
+			}
+			op := string(runes[start:i])
+			switch op {
+			case "=", "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+			default:
+				return nil, fmt.Errorf("unknown operator %q at position %d", op, start+1)
+			}
+			if op == "==" {
+				op = "="
+			}
+			tokens = append(tokens, exprToken{text: op, pos: start})
+
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !isOp(runes[i]) && !strings.ContainsRune(`()"'`, runes[i]) {
+				i++
+			}
+			tokens = append(tokens, exprToken{text: string(runes[start:i]), pos: start})
+		}
+	}
+	return tokens, nil
+}
+
+// exprParser is a recursive descent parser over tokens:
+//
+//	or         = and { "or" and }
+//	and        = not { "and" not }
+//	not        = "not" not | "(" or ")" | comparison
+//	comparison = field [ op value ]
+type exprParser struct {
+	tokens []exprToken
+	next   int
+}
+
+func (p *exprParser) peek() *exprToken {
+	if p.next < len(p.tokens) {
+		return &p.tokens[p.next]
+	}
+	return nil
+}
+
+// keyword reports whether the next token is the unquoted word kw, and
+// consumes it if so
+func (p *exprParser) keyword(kw string) bool {
+	tok := p.peek()
+	if tok != nil && !tok.quoted && strings.EqualFold(tok.text, kw) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t Track, now time.Time) bool { return l(t, now) || right(t, now) }
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(t Track, now time.Time) bool { return l(t, now) && right(t, now) }
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if p.keyword("not") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(t Track, now time.Time) bool { return !inner(t, now) }, nil
+	}
+
+	tok := p.peek()
+	if tok == nil {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if tok.text == "(" && !tok.quoted {
+		p.next++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.text != ")" || closing.quoted {
+			return nil, fmt.Errorf("missing ) for ( at position %d", tok.pos+1)
+		}
+		p.next++
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	fieldTok := p.tokens[p.next]
+	p.next++
+	if fieldTok.quoted || fieldTok.text == ")" {
+		return nil, fmt.Errorf("expected a field at position %d, got %q", fieldTok.pos+1, fieldTok.text)
+	}
+	field, ok := exprFieldByName(fieldTok.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q at position %d (available: %s)", fieldTok.text, fieldTok.pos+1, strings.Join(ExprFields, ", "))
+	}
+
+	opTok := p.peek()
+	if opTok == nil || opTok.quoted || !isExprOp(opTok.text) {
+		if field.boolean == nil {
+			return nil, fmt.Errorf("field %s needs a comparison, e.g. %s = ...", fieldTok.text, fieldTok.text)
+		}
+		return func(t Track, _ time.Time) bool { return field.boolean(t) }, nil
+	}
+	p.next++
+
+	valueTok := p.peek()
+	if valueTok == nil || (!valueTok.quoted && (valueTok.text == "(" || valueTok.text == ")")) {
+		return nil, fmt.Errorf("missing value after %s %s", fieldTok.text, opTok.text)
+	}
+	p.next++
+
+	node, err := field.compare(opTok.text, valueTok.text)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %q: %w", fieldTok.text, opTok.text, valueTok.text, err)
+	}
+	return node, nil
+}
+
+func isExprOp(s string) bool {
+	switch s {
+	case "=", "!=", "<", "<=", ">", ">=", "=~", "!~":
+		return true
+	}
+	return false
+}
+
+// exprField describes how to read and compare one track field. Exactly one
+// of the getters is set.
+type exprField struct {
+	text    func(Track) []string
+	number  func(Track, time.Time) (float64, bool)
+	parse   func(string) (float64, error)
+	date    func(Track) (time.Time, bool)
+	boolean func(Track) bool
+}
+
+func exprFieldByName(name string) (exprField, bool) {
+	single := func(get func(Track) string) func(Track) []string {
+		return func(t Track) []string {
+			if v := get(t); v != "" {
+				return []string{v}
+			}
+			return nil
+		}
+	}
+
+	switch strings.ToLower(name) {
+	case "name", "title":
+		return exprField{text: single(func(t Track) string { return t.Name })}, true
+	case "artist", "artists":
+		return exprField{text: func(t Track) []string { return t.Artists }}, true
+	case "album":
+		return exprField{text: single(func(t Track) string { return t.Album })}, true
+	case "added_by":
+		return exprField{text: single(func(t Track) string { return t.AddedBy })}, true
+	case "isrc":
+		return exprField{text: single(func(t Track) string { return t.ISRC })}, true
+	case "uri":
+		return exprField{text: single(func(t Track) string { return string(t.URI) })}, true
+	case "id":
+		return exprField{text: single(func(t Track) string { return string(t.ID) })}, true
+
+	case "popularity":
+		return exprField{
+			number: func(t Track, _ time.Time) (float64, bool) { return float64(t.Popularity), true },
+			parse:  parseExprNumber,
+		}, true
+	case "year":
+		return exprField{
+			number: func(t Track, _ time.Time) (float64, bool) {
+				year, err := strconv.Atoi(releaseYear(t.ReleaseDate))
+				return float64(year), err == nil
+			},
+			parse: parseExprNumber,
+		}, true
+	case "duration":
+		return exprField{
+			number: func(t Track, _ time.Time) (float64, bool) { return float64(t.Duration), t.Duration > 0 },
+			parse:  parseExprDuration,
+		}, true
+	case "age":
+		return exprField{
+			number: func(t Track, now time.Time) (float64, bool) {
+				return float64(now.Sub(t.AddedAt)), !t.AddedAt.IsZero()
+			},
+			parse: parseExprDuration,
+		}, true
+
+	case "added_at", "added":
+		return exprField{date: func(t Track) (time.Time, bool) { return t.AddedAt, !t.AddedAt.IsZero() }}, true
+	case "release_date", "released":
+		return exprField{date: func(t Track) (time.Time, bool) { return releaseTime(t.ReleaseDate, time.Local) }}, true
+
+	case "explicit":
+		return exprField{boolean: func(t Track) bool { return t.Explicit }}, true
+	}
+	return exprField{}, false
+}
+
+// compare builds the test for field op value
+func (f exprField) compare(op, value string) (exprNode, error) {
+	switch {
+	case f.text != nil:
+		return f.compareText(op, value)
+
+	case f.number != nil:
+		want, err := f.parse(value)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := orderedOp(op)
+		if err != nil {
+			return nil, err
+		}
+		return func(t Track, now time.Time) bool {
+			got, ok := f.number(t, now)
+			return ok && cmp(compareFloats(got, want))
+		}, nil
+
+	case f.date != nil:
+		day, ok := releaseTime(value, time.Local)
+		if !ok {
+			return nil, fmt.Errorf("expected a date like 2025-01-31")
+		}
+		// A date stands for the whole day, month or year it names
+		end := day.AddDate(0, 0, 1)
+		switch strings.Count(value, "-") {
+		case 0:
+			end = day.AddDate(1, 0, 0)
+		case 1:
+			end = day.AddDate(0, 1, 0)
+		}
+		cmp, err := orderedOp(op)
+		if err != nil {
+			return nil, err
+		}
+		return func(t Track, _ time.Time) bool {
+			got, ok := f.date(t)
+			if !ok {
+				return false
+			}
+			switch {
+			case got.Before(day):
+				return cmp(-1)
+			case got.Before(end):
+				return cmp(0)
+			default:
+				return cmp(1)
+			}
+		}, nil
+
+	default:
+		want, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false")
+		}
+		switch op {
+		case "=":
+			return func(t Track, _ time.Time) bool { return f.boolean(t) == want }, nil
+		case "!=":
+			return func(t Track, _ time.Time) bool { return f.boolean(t) != want }, nil
+		}
+		return nil, fmt.Errorf("operator %s can't compare true or false", op)
+	}
+}
+
+// compareText builds a test on a text field; a field with several values
+// matches = and =~ when any value does, and != and !~ when none does
+func (f exprField) compareText(op, value string) (exprNode, error) {
+	var test func(string) bool
+	negate := false
+	switch op {
+	case "=", "!=":
+		test = func(s string) bool { return strings.EqualFold(s, value) }
+		negate = op == "!="
+	case "=~", "!~":
+		re, err := regexp.Compile("(?i)" + value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		test = re.MatchString
+		negate = op == "!~"
+	default:
+		return nil, fmt.Errorf("operator %s doesn't apply to text; use =, !=, =~ or !~", op)
+	}
+
+	return func(t Track, _ time.Time) bool {
+		values := f.text(t)
+		if len(values) == 0 {
+			return false
+		}
+		for _, v := range values {
+			if test(v) {
+				return !negate
+			}
+		}
+		return negate
+	}, nil
+}
+
+// orderedOp turns an operator into a test on a comparison result
+func orderedOp(op string) (func(int) bool, error) {
+	switch op {
+	case "=":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=":
+		return func(c int) bool { return c != 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	}
+	return nil, fmt.Errorf("operator %s only applies to text", op)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func parseExprNumber(s string) (float64, error) {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number")
+	}
+	return n, nil
+}
+
+// parseExprDuration parses 3m30s, 3:30, 90d or 2w as a time.Duration,
+// returned as a float64 for comparison
+func parseExprDuration(s string) (float64, error) {
+	if mins, secs, ok := strings.Cut(s, ":"); ok {
+		m, err1 := strconv.Atoi(mins)
+		sec, err2 := strconv.Atoi(secs)
+		if err1 == nil && err2 == nil && sec < 60 {
+			return float64(time.Duration(m)*time.Minute + time.Duration(sec)*time.Second), nil
+		}
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			if v, err := strconv.ParseFloat(n, 64); err == nil {
+				return v * float64(unit), nil
+			}
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration like 10m, 3:30 or 90d")
+	}
+	return float64(d), nil
+}
@@ -0,0 +1,104 @@
+package playlist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exprTestTracks(now time.Time) []Track {
+	return []Track{
+		{ID: "foo", Name: "Intro", Artists: []string{"Foo Fighters"}, AddedBy: "ana", AddedAt: now.AddDate(0, 0, -10), Duration: 3 * time.Minute, Popularity: 70, ReleaseDate: "1997-05-20"},
+		{ID: "bob", Name: "Old Song", Artists: []string{"Band", "Guest"}, AddedBy: "bob", AddedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local), Duration: 4 * time.Minute, Popularity: 20, ReleaseDate: "2024"},
+		{ID: "long", Name: "Epic", Artists: []string{"Band"}, AddedBy: "bob", AddedAt: now.AddDate(0, 0, -1), Duration: 12 * time.Minute, Popularity: 50, ReleaseDate: "2025-01"},
+		{ID: "rude", Name: "Rude", Artists: []string{"Other"}, AddedBy: "ana", AddedAt: now.AddDate(0, 0, -400), Duration: 2 * time.Minute, Explicit: true},
+	}
+}
+
+func exprIDs(t *testing.T, src string, now time.Time) []string {
+	t.Helper()
+	expr, err := ParseExpr(src)
+	if err != nil {
+		t.Fatalf("ParseExpr(%q) error = %v", src, err)
+	}
+	return trackIDs(FilterTracks(exprTestTracks(now), expr, now))
+}
+
+func TestExpr_Match(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`artist =~ "Foo" or (added_by = "bob" and added_at < 2025-01-01) or duration > 10m or explicit`, []string{"foo", "bob", "long", "rude"}},
+		{`added_by = bob and added_at < 2025-01-01`, []string{"bob"}},
+		{`artist = "guest"`, []string{"bob"}},
+		{`artist != band`, []string{"foo", "rude"}},
+		{`artist !~ '^b'`, []string{"foo", "rude"}},
+		{`name = "old song"`, []string{"bob"}},
+		{`not explicit`, []string{"foo", "bob", "long"}},
+		{`explicit = false and popularity >= 50`, []string{"foo", "long"}},
+		{`duration <= 3:00`, []string{"foo", "rude"}},
+		{`age > 30d`, []string{"bob", "rude"}},
+		{`age < 2w and not (duration > 10m)`, []string{"foo"}},
+		{`year < 2000`, []string{"foo"}},
+		{`released = 2024`, []string{"bob"}},
+		{`released >= 2025-01-15`, nil},
+		{`release_date = 2025-01`, []string{"long"}},
+		{`added_at = 2024-06-01`, []string{"bob"}},
+		{`added_at > 2024-06-01 and added_at <= 2026-02-28`, []string{"foo", "long", "rude"}},
+		{`popularity > 10 and popularity < 60 or name = rude`, []string{"bob", "long", "rude"}},
+		{`NOT explicit AND added_by == ana`, []string{"foo"}},
+	}
+
+	for _, tt := range tests {
+		if got := exprIDs(t, tt.expr, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: matched %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExpr_MissingValues(t *testing.T) {
+	now := time.Now()
+	// rude has no release date, so it matches neither a comparison on year
+	// nor its opposite, only the negation of the whole comparison
+	if got, want := exprIDs(t, "year < 3000", now), []string{"foo", "bob", "long"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("year < 3000 = %v, want %v", got, want)
+	}
+	if got := exprIDs(t, "year >= 3000", now); got != nil {
+		t.Errorf("year >= 3000 = %v, want none", got)
+	}
+	if got, want := exprIDs(t, "not (year < 3000)", now), []string{"rude"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("not (year < 3000) = %v, want %v", got, want)
+	}
+}
+
+func TestParseExpr_Errors(t *testing.T) {
+	tests := map[string]string{
+		``:                        "empty",
+		`mood = happy`:            "unknown field",
+		`artist`:                  "needs a comparison",
+		`artist =`:                "missing value",
+		`artist < "x"`:            "doesn't apply to text",
+		`popularity = high`:       "expected a number",
+		`duration > long`:         "expected a duration",
+		`added_at < yesterday`:    "expected a date",
+		`explicit = maybe`:        "expected true or false",
+		`explicit > true`:         "can't compare",
+		`(explicit`:               "missing )",
+		`explicit)`:               "unexpected",
+		`name = "open`:            "unterminated",
+		`name => x`:               "unknown operator",
+		`artist =~ "("`:           "invalid regular expression",
+		`explicit and`:            "unexpected end",
+		`explicit or or explicit`: "unknown field",
+	}
+
+	for src, want := range tests {
+		_, err := ParseExpr(src)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseExpr(%q) error = %v, want error containing %q", src, err, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package playlist
+
+import (
+	"fmt"
+	"time"
+)
+
+// FreshDates lists the dates a fresh playlist can pick tracks by
+var FreshDates = []string{"added", "released"}
+
+// FreshOptions selects the tracks for a fresh playlist
+type FreshOptions struct {
+	// By is the date to look at, "added" (the default) or "released"
+	By string
+	// Days keeps tracks from the last Days days; it is ignored when Since
+	// or Until is set
+	Days int
+	// Since and Until bound the dates kept, by day and inclusive; either
+	// may be left zero for an open range
+	Since, Until time.Time
+}
+
+// Check reports whether the options select a valid range
+func (o FreshOptions) Check() error {
+	switch o.by() {
+	case "added", "released":
+	default:
+		return fmt.Errorf("unknown fresh date %q (use added or released)", o.By)
+	}
+
+	if !o.absolute() {
+		if o.Days <= 0 {
+			return fmt.Errorf("days must be greater than 0")
+		}
+		return nil
+	}
+	if !o.Since.IsZero() && !o.Until.IsZero() && o.Until.Before(o.Since) {
+		return fmt.Errorf("until (%s) is before since (%s)", o.Until.Format("2006-01-02"), o.Since.Format("2006-01-02"))
+	}
+	return nil
+}
+
+func (o FreshOptions) by() string {
+	if o.By == "" {
+		return "added"
+	}
+	return o.By
+}
+
+func (o FreshOptions) absolute() bool {
+	return !o.Since.IsZero() || !o.Until.IsZero()
+}
+
+// Describe says which tracks the options keep, e.g. "added in the last 30
+// days" or "released between 2026-01-01 and 2026-03-31"
+func (o FreshOptions) Describe() string {
+	const day = "2006-01-02"
+	switch {
+	case !o.absolute():
+		return fmt.Sprintf("%s in the last %d days", o.by(), o.Days)
+	case o.Until.IsZero():
+		return fmt.Sprintf("%s since %s", o.by(), o.Since.Format(day))
+	case o.Since.IsZero():
+		return fmt.Sprintf("%s up to %s", o.by(), o.Until.Format(day))
+	default:
+		return fmt.Sprintf("%s between %s and %s", o.by(), o.Since.Format(day), o.Until.Format(day))
+	}
+}
+
+// FilterFresh returns the tracks whose added or release date falls in the
+// range the options describe, measuring relative ranges back from now.
+// Tracks without the date are left out. Release dates Spotify only knows
+// to the month or year count as the first day of it.
+func FilterFresh(tracks []Track, opts FreshOptions, now time.Time) []Track {
+	keep := func(t time.Time) bool {
+		return t.After(now.AddDate(0, 0, -opts.Days))
+	}
+	if opts.absolute() {
+		keep = func(t time.Time) bool {
+			if !opts.Since.IsZero() && t.Before(startOfDay(opts.Since)) {
+				return false
+			}
+			if !opts.Until.IsZero() && !t.Before(startOfDay(opts.Until).AddDate(0, 0, 1)) {
+				return false
+			}
+			return true
+		}
+	}
+
+	return keepTracks(tracks, func(track Track) bool {
+		var date time.Time
+		if opts.by() == "released" {
+			var ok bool
+			if date, ok = releaseTime(track.ReleaseDate, now.Location()); !ok {
+				return false
+			}
+		} else {
+			date = track.AddedAt
+		}
+		return !date.IsZero() && keep(date)
+	})
+}
+
+// releaseTime parses a Spotify release date, which has day, month or year
+// precision, as the start of that day, month or year in loc
+func releaseTime(date string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.ParseInLocation(layout, date, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,94 @@
+package playlist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.ParseInLocation("2006-01-02", s, time.UTC)
+	return t
+}
+
+func TestFilterFresh_AddedDays(t *testing.T) {
+	now := day("2026-04-15")
+	tracks := []Track{
+		{ID: "new", AddedAt: now.AddDate(0, 0, -3)},
+		{ID: "old", AddedAt: now.AddDate(0, 0, -40)},
+		{ID: "undated"},
+	}
+
+	got := trackIDs(FilterFresh(tracks, FreshOptions{Days: 30}, now))
+	if want := []string{"new"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterFresh() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterFresh_ReleasedDays(t *testing.T) {
+	now := day("2026-04-15")
+	tracks := []Track{
+		{ID: "day", ReleaseDate: "2026-04-01", AddedAt: now},
+		{ID: "month", ReleaseDate: "2026-04"},
+		{ID: "year", ReleaseDate: "2026"},
+		{ID: "old", ReleaseDate: "1999-05-01", AddedAt: now},
+		{ID: "unknown", ReleaseDate: "", AddedAt: now},
+	}
+
+	got := trackIDs(FilterFresh(tracks, FreshOptions{By: "released", Days: 30}, now))
+	if want := []string{"day", "month"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterFresh() = %v, want %v", got, want)
+	}
+}
+
+func TestFilterFresh_Range(t *testing.T) {
+	now := day("2026-10-01")
+	tracks := []Track{
+		{ID: "before", AddedAt: day("2025-12-31").Add(23 * time.Hour)},
+		{ID: "first", AddedAt: day("2026-01-01")},
+		{ID: "last", AddedAt: day("2026-03-31").Add(23 * time.Hour)},
+		{ID: "after", AddedAt: day("2026-04-01")},
+	}
+
+	opts := FreshOptions{Since: day("2026-01-01"), Until: day("2026-03-31")}
+	got := trackIDs(FilterFresh(tracks, opts, now))
+	if want := []string{"first", "last"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterFresh() = %v, want %v", got, want)
+	}
+
+	opts = FreshOptions{Since: day("2026-03-01")}
+	got = trackIDs(FilterFresh(tracks, opts, now))
+	if want := []string{"last", "after"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterFresh(since only) = %v, want %v", got, want)
+	}
+}
+
+func TestFreshOptions_CheckAndDescribe(t *testing.T) {
+	tests := []struct {
+		opts    FreshOptions
+		wantErr bool
+		want    string
+	}{
+		{FreshOptions{Days: 30}, false, "added in the last 30 days"},
+		{FreshOptions{By: "released", Days: 7}, false, "released in the last 7 days"},
+		{FreshOptions{Since: day("2026-01-01"), Until: day("2026-03-31")}, false, "added between 2026-01-01 and 2026-03-31"},
+		{FreshOptions{By: "released", Until: day("2026-03-31")}, false, "released up to 2026-03-31"},
+		{FreshOptions{Since: day("2026-01-01")}, false, "added since 2026-01-01"},
+		{FreshOptions{}, true, ""},
+		{FreshOptions{By: "played", Days: 30}, true, ""},
+		{FreshOptions{Since: day("2026-04-01"), Until: day("2026-03-31")}, true, ""},
+	}
+
+	for _, tt := range tests {
+		err := tt.opts.Check()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: Check() error = %v, wantErr %v", tt.opts, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr {
+			if got := tt.opts.Describe(); got != tt.want {
+				t.Errorf("Describe() = %q, want %q", got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package playlist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// GenreMatchModes lists the ways a GenreFilter can compare genres
+var GenreMatchModes = []string{"contains", "exact", "regex"}
+
+// GenreFilter selects tracks by their artists' genres
+type GenreFilter struct {
+	// Include keeps tracks with a genre matching any of these; empty keeps
+	// every track not excluded
+	Include []string
+	// Exclude drops tracks with a genre matching any of these
+	Exclude []string
+	// Match is one of GenreMatchModes; empty means contains. All modes
+	// ignore case.
+	Match string
+	// Taxonomy also matches each genre's parent genre, so "metal" with
+	// exact matching finds every metal micro-genre; see ParentGenre
+	Taxonomy bool
+}
+
+// genreMatcher is a compiled GenreFilter
+type genreMatcher struct {
+	filter  GenreFilter
+	include []func(string) bool
+	exclude []func(string) bool
+}
+
+// Check reports whether the filter can be used
+func (f GenreFilter) Check() error {
+	_, err := f.compile()
+	return err
+}
+
+// Describe summarises the filter, e.g. "rock, punk (exact), not pop punk"
+func (f GenreFilter) Describe() string {
+	desc := strings.Join(f.Include, ", ")
+	if desc == "" {
+		desc = "any genre"
+	}
+	if f.match() != "contains" {
+		desc += " (" + f.match() + ")"
+	}
+	if f.Taxonomy {
+		desc += " with parent genres"
+	}
+	if len(f.Exclude) > 0 {
+		desc += ", not " + strings.Join(f.Exclude, ", ")
+	}
+	return desc
+}
+
+func (f GenreFilter) match() string {
+	if f.Match == "" {
+		return "contains"
+	}
+	return f.Match
+}
+
+func (f GenreFilter) compile() (*genreMatcher, error) {
+	if len(f.Include) == 0 && len(f.Exclude) == 0 {
+		return nil, fmt.Errorf("at least one genre to include or exclude is required")
+	}
+
+	m := &genreMatcher{filter: f}
+	for _, list := range []struct {
+		patterns []string
+		into     *[]func(string) bool
+	}{{f.Include, &m.include}, {f.Exclude, &m.exclude}} {
+		for _, pattern := range list.patterns {
+			fn, err := genrePattern(f.match(), pattern)
+			if err != nil {
+				return nil, err
+			}
+			*list.into = append(*list.into, fn)
+		}
+	}
+	return m, nil
+}
+
+// genrePattern returns a case-insensitive test for one genre pattern
+func genrePattern(mode, pattern string) (func(string) bool, error) {
+	lower := strings.ToLower(strings.TrimSpace(pattern))
+	if lower == "" {
+		return nil, fmt.Errorf("empty genre")
+	}
+
+	switch mode {
+	case "contains":
+		return func(genre string) bool { return strings.Contains(strings.ToLower(genre), lower) }, nil
+	case "exact":
+		return func(genre string) bool { return strings.ToLower(genre) == lower }, nil
+	case "regex":
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid genre pattern %q: %w", pattern, err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("unknown genre match %q (use %s)", mode, strings.Join(GenreMatchModes, ", "))
+	}
+}
+
+// keep reports whether a track with genres passes the filter
+func (m *genreMatcher) keep(genres []string) bool {
+	if m.filter.Taxonomy {
+		genres = append(append([]string(nil), genres...), ParentGenres(genres)...)
+	}
+
+	matchesAny := func(tests []func(string) bool) bool {
+		for _, test := range tests {
+			for _, genre := range genres {
+				if test(genre) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if matchesAny(m.exclude) {
+		return false
+	}
+	return len(m.include) == 0 || matchesAny(m.include)
+}
+
+// FilterByGenre returns the tracks whose genres, from trackGenres, pass
+// the filter
+func FilterByGenre(tracks []Track, trackGenres map[spotify.ID][]string, filter GenreFilter) ([]Track, error) {
+	m, err := filter.compile()
+	if err != nil {
+		return nil, err
+	}
+	return keepTracks(tracks, func(t Track) bool {
+		return m.keep(trackGenres[t.ID])
+	}), nil
+}
@@ -0,0 +1,70 @@
+package playlist
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func genreTestTracks() ([]Track, map[spotify.ID][]string) {
+	tracks := []Track{{ID: "indie"}, {ID: "rock"}, {ID: "billy"}, {ID: "poppunk"}, {ID: "death"}, {ID: "none"}}
+	trackGenres := map[spotify.ID][]string{
+		"indie":   {"indie rock"},
+		"rock":    {"Rock", "classic rock"},
+		"billy":   {"rockabilly"},
+		"poppunk": {"pop punk", "punk"},
+		"death":   {"melodic death metal"},
+	}
+	return tracks, trackGenres
+}
+
+func TestFilterByGenre(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter GenreFilter
+		want   []string
+	}{
+		{"contains", GenreFilter{Include: []string{"rock"}}, []string{"indie", "rock", "billy"}},
+		{"exact ignores case", GenreFilter{Include: []string{"rock"}, Match: "exact"}, []string{"rock"}},
+		{"regex", GenreFilter{Include: []string{`\brock$`}, Match: "regex"}, []string{"indie", "rock"}},
+		{"several", GenreFilter{Include: []string{"rock", "punk"}, Match: "exact"}, []string{"rock", "poppunk"}},
+		{"exclude", GenreFilter{Include: []string{"rock", "punk"}, Exclude: []string{"pop punk", "rockabilly"}}, []string{"indie", "rock"}},
+		{"exclude only", GenreFilter{Exclude: []string{"rock"}}, []string{"poppunk", "death", "none"}},
+		{"taxonomy", GenreFilter{Include: []string{"metal"}, Match: "exact", Taxonomy: true}, []string{"death"}},
+		{"taxonomy exclude", GenreFilter{Include: []string{"rock"}, Exclude: []string{"Punk"}, Match: "exact", Taxonomy: true}, []string{"indie", "rock", "billy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracks, trackGenres := genreTestTracks()
+			kept, err := FilterByGenre(tracks, trackGenres, tt.filter)
+			if err != nil {
+				t.Fatalf("FilterByGenre() error = %v", err)
+			}
+			if got := trackIDs(kept); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterByGenre() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenreFilter_Check(t *testing.T) {
+	for _, f := range []GenreFilter{
+		{},
+		{Include: []string{"rock"}, Match: "fuzzy"},
+		{Include: []string{"("}, Match: "regex"},
+		{Include: []string{" "}},
+	} {
+		if err := f.Check(); err == nil {
+			t.Errorf("Check(%+v) = nil, want error", f)
+		}
+	}
+}
+
+func TestGenreFilter_Describe(t *testing.T) {
+	f := GenreFilter{Include: []string{"rock", "punk"}, Exclude: []string{"pop punk"}, Match: "exact"}
+	if got, want := f.Describe(), "rock, punk (exact), not pop punk"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
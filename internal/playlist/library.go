@@ -0,0 +1,86 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// ListPlaylists returns every playlist the current user owns or follows
+func (m *Manager) ListPlaylists(ctx context.Context) ([]spotify.SimplePlaylist, error) {
+	limit := 50
+	offset := 0
+	var all []spotify.SimplePlaylist
+
+	for {
+		playlists, err := m.client.CurrentUsersPlaylists(ctx, spotify.Limit(limit), spotify.Offset(offset))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get playlists: %w", err)
+		}
+
+		all = append(all, playlists.Playlists...)
+
+		if len(playlists.Playlists) < limit {
+			break
+		}
+		offset += limit
+	}
+
+	return all, nil
+}
+
+// SelectPlaylists returns the current user's playlists, limited to those
+// they own when ownedOnly is set and to names matching the glob pattern
+// (case insensitive) when pattern is not empty
+func (m *Manager) SelectPlaylists(ctx context.Context, ownedOnly bool, pattern string) ([]spotify.SimplePlaylist, error) {
+	var userID string
+	if ownedOnly {
+		user, err := m.client.CurrentUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %w", err)
+		}
+		userID = user.ID
+	}
+
+	playlists, err := m.ListPlaylists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []spotify.SimplePlaylist
+	for _, p := range playlists {
+		if ownedOnly && p.Owner.ID != userID {
+			continue
+		}
+		if pattern != "" && !MatchName(pattern, p.Name) {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	return selected, nil
+}
+
+// MatchName reports whether a playlist name matches a glob pattern such as
+// "Team *", ignoring case. '*' matches any run of characters (including
+// '/') and '?' matches a single character.
+func MatchName(pattern, name string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+
+	return regexp.MustCompile(expr.String()).MatchString(name)
+}
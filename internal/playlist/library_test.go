@@ -0,0 +1,85 @@
+package playlist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestMatchName(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"Team *", "Team Alpha", true},
+		{"team *", "TEAM Beta", true},
+		{"Team *", "The Team", false},
+		{"*mix*", "Friday Mix / Vol. 2", true},
+		{"Road Trip ?", "Road Trip 2", true},
+		{"Road Trip ?", "Road Trip 10", false},
+		{"[Old]*", "[Old] Favourites", true},
+	}
+
+	for _, tt := range tests {
+		if got := MatchName(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchName(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectPlaylists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/me":
+			w.Write([]byte(`{"id":"me"}`))
+		case "/me/playlists":
+			w.Write([]byte(`{"items":[
+				{"id":"p1","name":"Team Alpha","owner":{"id":"me"}},
+				{"id":"p2","name":"Team Beta","owner":{"id":"someone"}},
+				{"id":"p3","name":"Workout","owner":{"id":"me"}}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+
+	tests := []struct {
+		name      string
+		ownedOnly bool
+		pattern   string
+		want      []spotify.ID
+	}{
+		{"owned", true, "", []spotify.ID{"p1", "p3"}},
+		{"match", false, "team *", []spotify.ID{"p1", "p2"}},
+		{"owned and match", true, "Team *", []spotify.ID{"p1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlists, err := manager.SelectPlaylists(context.Background(), tt.ownedOnly, tt.pattern)
+			if err != nil {
+				t.Fatalf("SelectPlaylists() error = %v", err)
+			}
+			var ids []spotify.ID
+			for _, p := range playlists {
+				ids = append(ids, p.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("SelectPlaylists() = %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Errorf("SelectPlaylists() = %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -2,81 +2,239 @@ package playlist
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/petabloc/spotify-shuffle/internal/cache"
+	"github.com/petabloc/spotify-shuffle/internal/progress"
 	"github.com/zmb3/spotify/v2"
 )
 
 type Manager struct {
-	client *spotify.Client
+	client      *spotify.Client
+	cache       *cache.Cache
+	concurrency int
+	progress    progress.Reporter
 }
 
 // NewManager creates a new playlist manager
 func NewManager(client *spotify.Client) *Manager {
-	return &Manager{client: client}
+	return &Manager{client: client, concurrency: DefaultConcurrency}
+}
+
+// SetCache makes the manager read and store metadata in c. A nil cache
+// disables caching.
+func (m *Manager) SetCache(c *cache.Cache) {
+	m.cache = c
+	if c == nil {
+		return
+	}
+	for bucket, age := range bucketMaxAge {
+		c.SetMaxAge(bucket, age)
+	}
 }
 
 // Track represents a track with metadata
 type Track struct {
-	ID      spotify.ID
-	Name    string
-	Artists []string
-	URI     spotify.URI
-	AddedAt time.Time
+	ID          spotify.ID
+	Name        string
+	Artists     []string
+	ArtistIDs   []spotify.ID
+	Album       string
+	AlbumID     spotify.ID
+	ReleaseDate string
+	Duration    time.Duration
+	Popularity  int
+	Explicit    bool
+	ISRC        string
+	URI         spotify.URI
+	AddedAt     time.Time
+	AddedBy     string
 }
 
-// GetPlaylistTracks retrieves all tracks from a playlist
+// GetPlaylistTracks retrieves all tracks from a playlist. With a cache
+// attached, the tracks are only downloaded when the playlist's snapshot
+// has changed since the last call.
 func (m *Manager) GetPlaylistTracks(ctx context.Context, playlistID spotify.ID) ([]Track, error) {
-	var tracks []Track
-	limit := 50
-	offset := 0
+	if m.cache == nil {
+		return m.fetchPlaylistTracks(ctx, playlistID)
+	}
 
-	for {
-		page, err := m.client.GetPlaylistTracks(ctx, playlistID, spotify.Limit(limit), spotify.Offset(offset))
+	snapshotID, err := m.PlaylistSnapshot(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached cachedPlaylist
+	if m.cacheGet(bucketPlaylists, string(playlistID), 0, &cached) &&
+		cached.Version == playlistCacheVersion && cached.SnapshotID == snapshotID {
+		return cached.Tracks, nil
+	}
+
+	tracks, err := m.fetchPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.cachePut(bucketPlaylists, string(playlistID), cachedPlaylist{
+		Version:    playlistCacheVersion,
+		SnapshotID: snapshotID,
+		Tracks:     tracks,
+	})
+	return tracks, nil
+}
+
+// PlaylistSnapshot returns the playlist's current snapshot ID, which changes
+// whenever its tracks do
+func (m *Manager) PlaylistSnapshot(ctx context.Context, playlistID spotify.ID) (string, error) {
+	playlist, err := m.client.GetPlaylist(ctx, playlistID, spotify.Fields("snapshot_id"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get playlist snapshot: %w", err)
+	}
+	return playlist.SnapshotID, nil
+}
+
+// PlaylistName returns the playlist's name
+func (m *Manager) PlaylistName(ctx context.Context, playlistID spotify.ID) (string, error) {
+	playlist, err := m.client.GetPlaylist(ctx, playlistID, spotify.Fields("name"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get playlist name: %w", err)
+	}
+	return playlist.Name, nil
+}
+
+// PlaylistWithTracks returns a playlist's snapshot ID, name and tracks. They
+// come from one playlist fetch, which carries the first page of tracks, so
+// the snapshot ID always describes the tracks returned with it; should a
+// longer playlist change while its other pages are fetched, it is fetched
+// again.
+func (m *Manager) PlaylistWithTracks(ctx context.Context, playlistID spotify.ID) (snapshotID, name string, tracks []Track, err error) {
+	const attempts = 3
+
+	for i := 0; i < attempts; i++ {
+		playlist, err := m.client.GetPlaylist(ctx, playlistID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+			return "", "", nil, fmt.Errorf("failed to get playlist: %w", err)
+		}
+		tracks, err := m.fetchRemainingTracks(ctx, playlistID, &playlist.Tracks)
+		if err != nil {
+			return "", "", nil, err
 		}
 
-		for _, item := range page.Tracks {
-			if item.Track.ID == "" {
+		if playlist.Tracks.Total > playlistPageSize {
+			current, err := m.PlaylistSnapshot(ctx, playlistID)
+			if err != nil {
+				return "", "", nil, err
+			}
+			if current != playlist.SnapshotID {
 				continue
 			}
+		}
 
-			track := item.Track
-			var artistNames []string
-			for _, artist := range track.Artists {
-				artistNames = append(artistNames, artist.Name)
-			}
+		m.cachePut(bucketPlaylists, string(playlistID), cachedPlaylist{
+			Version:    playlistCacheVersion,
+			SnapshotID: playlist.SnapshotID,
+			Tracks:     tracks,
+		})
+		return playlist.SnapshotID, playlist.Name, tracks, nil
+	}
+	return "", "", nil, fmt.Errorf("playlist kept changing while it was read, tried %d times", attempts)
+}
 
-			addedAt := time.Time{}
-			if item.AddedAt != "" {
-				if parsed, err := time.Parse(time.RFC3339, item.AddedAt); err == nil {
-					addedAt = parsed
-				}
-			}
+// playlistPageSize is the most tracks Spotify returns per page
+const playlistPageSize = 100
+
+// fetchPlaylistTracks downloads every track of a playlist
+func (m *Manager) fetchPlaylistTracks(ctx context.Context, playlistID spotify.ID) ([]Track, error) {
+	first, err := m.client.GetPlaylistTracks(ctx, playlistID, spotify.Limit(playlistPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+	return m.fetchRemainingTracks(ctx, playlistID, first)
+}
+
+// fetchRemainingTracks returns the tracks of a playlist given its first
+// page, which tells us how many tracks there are. The remaining pages are
+// fetched in parallel and reassembled in playlist order.
+func (m *Manager) fetchRemainingTracks(ctx context.Context, playlistID spotify.ID, first *spotify.PlaylistTrackPage) ([]Track, error) {
+	const limit = playlistPageSize
+
+	pageCount := 1
+	if first.Total > limit {
+		pageCount = (int(first.Total) + limit - 1) / limit
+	}
+	pages := make([][]spotify.PlaylistTrack, pageCount)
+	pages[0] = first.Tracks
+
+	fetched := m.startStage(progress.StageFetch, pageCount)
+	fetched.add(1)
 
-			tracks = append(tracks, Track{
-				ID:      track.ID,
-				Name:    track.Name,
-				Artists: artistNames,
-				URI:     track.URI,
-				AddedAt: addedAt,
-			})
+	err := m.forEach(ctx, pageCount-1, func(ctx context.Context, i int) error {
+		offset := (i + 1) * limit
+		page, err := m.client.GetPlaylistTracks(ctx, playlistID, spotify.Limit(limit), spotify.Offset(offset))
+		if err != nil {
+			return fmt.Errorf("failed to get playlist tracks: %w", err)
 		}
+		pages[i+1] = page.Tracks
+		fetched.add(1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if len(page.Tracks) < limit {
-			break
+	var tracks []Track
+	for _, page := range pages {
+		for _, item := range page {
+			if item.Track.ID == "" {
+				continue
+			}
+			tracks = append(tracks, newTrack(item))
 		}
-		offset += limit
 	}
 
 	return tracks, nil
 }
 
+// newTrack converts a playlist item into a Track
+func newTrack(item spotify.PlaylistTrack) Track {
+	track := item.Track
+	var artistNames []string
+	var artistIDs []spotify.ID
+	for _, artist := range track.Artists {
+		artistNames = append(artistNames, artist.Name)
+		artistIDs = append(artistIDs, artist.ID)
+	}
+
+	addedAt := time.Time{}
+	if item.AddedAt != "" {
+		if parsed, err := time.Parse(time.RFC3339, item.AddedAt); err == nil {
+			addedAt = parsed
+		}
+	}
+
+	return Track{
+		ID:          track.ID,
+		Name:        track.Name,
+		Artists:     artistNames,
+		ArtistIDs:   artistIDs,
+		Album:       track.Album.Name,
+		AlbumID:     track.Album.ID,
+		ReleaseDate: track.Album.ReleaseDate,
+		Duration:    track.TimeDuration(),
+		Popularity:  int(track.Popularity),
+		Explicit:    track.Explicit,
+		ISRC:        track.ExternalIDs["isrc"],
+		URI:         track.URI,
+		AddedAt:     addedAt,
+		AddedBy:     item.AddedBy.ID,
+	}
+}
+
 // ShufflePlaylist randomizes the order of tracks in a playlist
 func (m *Manager) ShufflePlaylist(ctx context.Context, playlistID spotify.ID) error {
 	tracks, err := m.GetPlaylistTracks(ctx, playlistID)
@@ -98,7 +256,7 @@ func (m *Manager) ShufflePlaylist(ctx context.Context, playlistID spotify.ID) er
 	})
 
 	// Replace playlist with shuffled tracks
-	return m.replacePlaylistTracks(ctx, playlistID, uris)
+	return m.rewritePlaylistTracks(ctx, playlistID, trackURIs(tracks), uris)
 }
 
 // SortPlaylist sorts playlist tracks by the specified criteria
@@ -124,6 +282,9 @@ func (m *Manager) SortPlaylistByTitle(ctx context.Context, playlistID spotify.ID
 		return fmt.Errorf("playlist is empty")
 	}
 
+	// Keep the current order so an interrupted write can be rolled back
+	original := trackURIs(tracks)
+
 	// Sort by title (case insensitive)
 	sort.Slice(tracks, func(i, j int) bool {
 		return strings.ToLower(tracks[i].Name) < strings.ToLower(tracks[j].Name)
@@ -136,7 +297,7 @@ func (m *Manager) SortPlaylistByTitle(ctx context.Context, playlistID spotify.ID
 	}
 
 	// Replace playlist with sorted tracks
-	return m.replacePlaylistTracks(ctx, playlistID, uris)
+	return m.rewritePlaylistTracks(ctx, playlistID, original, uris)
 }
 
 // SortPlaylistByArtist sorts playlist tracks alphabetically by artist
@@ -150,6 +311,9 @@ func (m *Manager) SortPlaylistByArtist(ctx context.Context, playlistID spotify.I
 		return fmt.Errorf("playlist is empty")
 	}
 
+	// Keep the current order so an interrupted write can be rolled back
+	original := trackURIs(tracks)
+
 	// Sort by first artist (case insensitive)
 	sort.Slice(tracks, func(i, j int) bool {
 		artistI := ""
@@ -170,7 +334,7 @@ func (m *Manager) SortPlaylistByArtist(ctx context.Context, playlistID spotify.I
 	}
 
 	// Replace playlist with sorted tracks
-	return m.replacePlaylistTracks(ctx, playlistID, uris)
+	return m.rewritePlaylistTracks(ctx, playlistID, original, uris)
 }
 
 // ReversePlaylist reverses the order of tracks in a playlist
@@ -191,7 +355,7 @@ func (m *Manager) ReversePlaylist(ctx context.Context, playlistID spotify.ID) er
 	}
 
 	// Replace playlist with reversed tracks
-	return m.replacePlaylistTracks(ctx, playlistID, uris)
+	return m.rewritePlaylistTracks(ctx, playlistID, trackURIs(tracks), uris)
 }
 
 // RemoveOldTracks removes tracks older than specified days
@@ -216,7 +380,7 @@ func (m *Manager) RemoveOldTracks(ctx context.Context, playlistID spotify.ID, da
 	}
 
 	// Replace playlist with tracks to keep
-	err = m.replacePlaylistTracks(ctx, playlistID, tracksToKeep)
+	err = m.rewritePlaylistTracks(ctx, playlistID, trackURIs(tracks), tracksToKeep)
 	return removedCount, err
 }
 
@@ -249,57 +413,125 @@ func (m *Manager) RemoveTracksByArtist(ctx context.Context, playlistID spotify.I
 	}
 
 	// Replace playlist with tracks to keep
-	err = m.replacePlaylistTracks(ctx, playlistID, tracksToKeep)
+	err = m.rewritePlaylistTracks(ctx, playlistID, trackURIs(tracks), tracksToKeep)
 	return removedCount, err
 }
 
-// replacePlaylistTracks replaces all tracks in a playlist with new ones
-func (m *Manager) replacePlaylistTracks(ctx context.Context, playlistID spotify.ID, uris []spotify.URI) error {
-	if len(uris) == 0 {
-		// Clear playlist
-		return m.client.ReplacePlaylistTracks(ctx, playlistID)
+// RemoveTracksWhere removes every track matching a filter expression
+func (m *Manager) RemoveTracksWhere(ctx context.Context, playlistID spotify.ID, expr *Expr) (int, error) {
+	tracks, err := m.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return 0, err
 	}
 
-	// Spotify API limit is 100 tracks per request
-	const batchSize = 100
+	now := time.Now()
+	tracksToKeep := trackURIs(keepTracks(tracks, func(t Track) bool { return !expr.root(t, now) }))
 
-	// Replace first batch
-	firstBatch := uris
-	if len(uris) > batchSize {
-		firstBatch = uris[:batchSize]
+	removedCount := len(tracks) - len(tracksToKeep)
+	if removedCount == 0 {
+		return 0, nil
 	}
 
-	// Convert URIs to IDs for ReplacePlaylistTracks
-	var firstBatchIDs []spotify.ID
-	for _, uri := range firstBatch {
-		firstBatchIDs = append(firstBatchIDs, spotify.ID(strings.TrimPrefix(string(uri), "spotify:track:")))
+	// Replace playlist with tracks to keep
+	err = m.rewritePlaylistTracks(ctx, playlistID, trackURIs(tracks), tracksToKeep)
+	return removedCount, err
+}
+
+// rewritePlaylistTracks replaces the tracks of an existing playlist. If the
+// write is interrupted, the playlist is restored to original.
+func (m *Manager) rewritePlaylistTracks(ctx context.Context, playlistID spotify.ID, original, uris []spotify.URI) error {
+	err := m.replacePlaylistTracks(ctx, playlistID, uris)
+
+	var interrupted *InterruptedWriteError
+	if !errors.As(err, &interrupted) {
+		return err
 	}
 
-	if err := m.client.ReplacePlaylistTracks(ctx, playlistID, firstBatchIDs...); err != nil {
-		return fmt.Errorf("failed to replace playlist tracks: %w", err)
+	// Rolling back must not be cut short by the same cancellation
+	if _, rollbackErr := m.writePlaylistTracks(context.WithoutCancel(ctx), playlistID, original, nil); rollbackErr != nil {
+		interrupted.RollbackErr = rollbackErr
+	} else {
+		interrupted.RolledBack = true
+	}
+	return interrupted
+}
+
+// replacePlaylistTracks replaces all tracks in a playlist with new ones.
+// Cancelling ctx stops the write between batches; the batch in flight is
+// always completed, and an *InterruptedWriteError describes what the
+// playlist holds.
+func (m *Manager) replacePlaylistTracks(ctx context.Context, playlistID spotify.ID, uris []spotify.URI) error {
+	// The cached copy is superseded by whatever snapshot this write produces
+	m.cacheDelete(bucketPlaylists, string(playlistID))
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("playlist left unchanged: %w", err)
+	}
+
+	written := m.startStage(progress.StageWrite, len(uris))
+	n, err := m.writePlaylistTracks(ctx, playlistID, uris, written)
+	if err != nil && ctx.Err() != nil && n > 0 {
+		return &InterruptedWriteError{PlaylistID: playlistID, Written: n, Total: len(uris), Cause: ctx.Err()}
+	}
+	return err
+}
+
+// writePlaylistTracks writes uris to a playlist in batches and returns how
+// many tracks were written. Requests are not cancelled mid-flight; ctx is
+// only checked between batches.
+func (m *Manager) writePlaylistTracks(ctx context.Context, playlistID spotify.ID, uris []spotify.URI, written *stageProgress) (int, error) {
+	writeCtx := context.WithoutCancel(ctx)
+
+	if len(uris) == 0 {
+		// Clear playlist
+		return 0, m.client.ReplacePlaylistTracks(writeCtx, playlistID)
 	}
 
-	// Add remaining batches
-	for i := batchSize; i < len(uris); i += batchSize {
+	// Spotify API limit is 100 tracks per request
+	const batchSize = 100
+
+	count := 0
+	for i := 0; i < len(uris); i += batchSize {
+		if i > 0 && ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+
 		end := i + batchSize
 		if end > len(uris) {
 			end = len(uris)
 		}
-		batch := uris[i:end]
 
-		// Convert URIs to IDs for AddTracksToPlaylist
+		// Convert URIs to IDs for the API
 		var batchIDs []spotify.ID
-		for _, uri := range batch {
+		for _, uri := range uris[i:end] {
 			batchIDs = append(batchIDs, spotify.ID(strings.TrimPrefix(string(uri), "spotify:track:")))
 		}
 
-		_, err := m.client.AddTracksToPlaylist(ctx, playlistID, batchIDs...)
-		if err != nil {
-			return fmt.Errorf("failed to add tracks to playlist: %w", err)
+		// The first batch replaces the playlist contents; the rest are appended
+		if i == 0 {
+			if err := m.client.ReplacePlaylistTracks(writeCtx, playlistID, batchIDs...); err != nil {
+				return count, fmt.Errorf("failed to replace playlist tracks: %w", err)
+			}
+		} else if _, err := m.client.AddTracksToPlaylist(writeCtx, playlistID, batchIDs...); err != nil {
+			return count, fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+
+		count += len(batchIDs)
+		if written != nil {
+			written.add(len(batchIDs))
 		}
 	}
 
-	return nil
+	return count, nil
+}
+
+// trackURIs returns the URIs of tracks, in order
+func trackURIs(tracks []Track) []spotify.URI {
+	uris := make([]spotify.URI, len(tracks))
+	for i, track := range tracks {
+		uris[i] = track.URI
+	}
+	return uris
 }
 
 // GetUniqueArtists returns all unique artists in a playlist
@@ -327,12 +559,18 @@ func (m *Manager) GetUniqueArtists(ctx context.Context, playlistID spotify.ID) (
 
 // CreatePlaylist creates a new playlist
 func (m *Manager) CreatePlaylist(ctx context.Context, name, description string, public bool) (spotify.ID, error) {
+	return m.createPlaylist(ctx, name, description, public, false)
+}
+
+// createPlaylist creates a new playlist owned by the current user.
+// Collaborative playlists must not be public.
+func (m *Manager) createPlaylist(ctx context.Context, name, description string, public, collaborative bool) (spotify.ID, error) {
 	user, err := m.client.CurrentUser(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get current user: %w", err)
 	}
 
-	playlist, err := m.client.CreatePlaylistForUser(ctx, user.ID, name, description, public, false)
+	playlist, err := m.client.CreatePlaylistForUser(ctx, user.ID, name, description, public, collaborative)
 	if err != nil {
 		return "", fmt.Errorf("failed to create playlist: %w", err)
 	}
@@ -367,94 +605,115 @@ func (m *Manager) FindPlaylistByName(ctx context.Context, name string) (spotify.
 	return "", fmt.Errorf("playlist not found")
 }
 
-// CreateFreshPlaylist creates a playlist with tracks added within the last N days
-func (m *Manager) CreateFreshPlaylist(ctx context.Context, sourcePlaylistID spotify.ID, name string, days int, overwrite bool) (int, error) {
-	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
-	if err != nil {
-		return 0, err
-	}
-
-	// Filter tracks by date
-	cutoffDate := time.Now().AddDate(0, 0, -days)
-	var freshTracks []spotify.URI
-
-	for _, track := range tracks {
-		if !track.AddedAt.IsZero() && track.AddedAt.After(cutoffDate) {
-			freshTracks = append(freshTracks, track.URI)
-		}
-	}
-
-	if len(freshTracks) == 0 {
-		return 0, fmt.Errorf("no tracks found within the last %d days", days)
-	}
-
+// writeNamedPlaylist fills the playlist called name with uris, creating it
+// with description if there is none. An existing playlist is an error
+// unless overwrite is set, in which case its tracks are replaced and its
+// description updated to match.
+func (m *Manager) writeNamedPlaylist(ctx context.Context, name, description string, uris []spotify.URI, overwrite bool) error {
 	// Check if playlist exists
-	var playlistID spotify.ID
-	if existingID, err := m.FindPlaylistByName(ctx, name); err == nil {
+	playlistID, err := m.FindPlaylistByName(ctx, name)
+	if err == nil {
 		if !overwrite {
-			return 0, fmt.Errorf("playlist '%s' already exists", name)
+			return fmt.Errorf("playlist '%s' already exists", name)
 		}
-		playlistID = existingID
-		// Clear existing playlist
-		if err := m.client.ReplacePlaylistTracks(ctx, playlistID); err != nil {
-			return 0, fmt.Errorf("failed to clear existing playlist: %w", err)
+		// Keep the description in step with the new contents
+		if err := m.client.ChangePlaylistDescription(ctx, playlistID, description); err != nil {
+			return fmt.Errorf("failed to update playlist description: %w", err)
 		}
 	} else {
 		// Create new playlist
-		description := fmt.Sprintf("Fresh tracks from the last %d days", days)
-		newID, err := m.CreatePlaylist(ctx, name, description, false)
+		playlistID, err = m.CreatePlaylist(ctx, name, description, false)
 		if err != nil {
-			return 0, err
+			return err
 		}
-		playlistID = newID
 	}
 
 	// Add tracks to playlist
-	if err := m.replacePlaylistTracks(ctx, playlistID, freshTracks); err != nil {
-		return 0, fmt.Errorf("failed to add tracks to playlist: %w", err)
+	if err := m.replacePlaylistTracks(ctx, playlistID, uris); err != nil {
+		return fmt.Errorf("failed to add tracks to playlist: %w", err)
+	}
+	return nil
+}
+
+// CreateFreshPlaylist creates a playlist with the tracks added or released
+// within the range opts describes
+func (m *Manager) CreateFreshPlaylist(ctx context.Context, sourcePlaylistID spotify.ID, name string, opts FreshOptions, overwrite bool) (int, error) {
+	if err := opts.Check(); err != nil {
+		return 0, err
+	}
+
+	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
+	if err != nil {
+		return 0, err
+	}
+
+	freshTracks := trackURIs(FilterFresh(tracks, opts, time.Now()))
+	if len(freshTracks) == 0 {
+		return 0, fmt.Errorf("no tracks found %s", opts.Describe())
+	}
+
+	description := "Fresh tracks " + opts.Describe()
+	if err := m.writeNamedPlaylist(ctx, name, description, freshTracks, overwrite); err != nil {
+		return 0, err
 	}
 
 	return len(freshTracks), nil
 }
 
-// CreateChunkPlaylists creates multiple playlists with random chunks of tracks
-func (m *Manager) CreateChunkPlaylists(ctx context.Context, sourcePlaylistID spotify.ID, baseName string, chunkSize int, overwrite bool) (int, error) {
+// CreateFilteredPlaylist creates a playlist with the tracks matching a
+// filter expression
+func (m *Manager) CreateFilteredPlaylist(ctx context.Context, sourcePlaylistID spotify.ID, name string, expr *Expr, overwrite bool) (int, error) {
 	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
 	if err != nil {
 		return 0, err
 	}
 
-	if len(tracks) == 0 {
-		return 0, fmt.Errorf("source playlist is empty")
+	matched := trackURIs(FilterTracks(tracks, expr, time.Now()))
+	if len(matched) == 0 {
+		return 0, fmt.Errorf("no tracks match %s", expr)
 	}
 
-	// Extract URIs and shuffle
-	uris := make([]spotify.URI, len(tracks))
-	for i, track := range tracks {
-		uris[i] = track.URI
+	description := "Tracks where " + expr.String()
+	if err := m.writeNamedPlaylist(ctx, name, description, matched, overwrite); err != nil {
+		return 0, err
 	}
-	rand.Shuffle(len(uris), func(i, j int) {
-		uris[i], uris[j] = uris[j], uris[i]
-	})
 
-	// Calculate number of chunks
-	totalChunks := (len(uris) + chunkSize - 1) / chunkSize
+	return len(matched), nil
+}
+
+// CreateChunkPlaylists splits a playlist into smaller playlists as planned
+// by PlanChunkPlaylists. Existing playlists with a chunk's name are skipped,
+// or overwritten when overwrite is set.
+func (m *Manager) CreateChunkPlaylists(ctx context.Context, sourcePlaylistID spotify.ID, baseName string, opts ChunkOptions, overwrite bool) (int, error) {
+	chunks, err := m.PlanChunkPlaylists(ctx, sourcePlaylistID, baseName, opts)
+	if err != nil {
+		return 0, err
+	}
+	return m.CreatePlannedPlaylists(ctx, chunks, overwrite)
+}
+
+// CreatePlannedPlaylists creates a playlist for each planned chunk and
+// returns how many were written. Existing playlists with a chunk's name are
+// skipped, or overwritten when overwrite is set.
+func (m *Manager) CreatePlannedPlaylists(ctx context.Context, chunks []Chunk, overwrite bool) (int, error) {
+	totalChunks := len(chunks)
 	createdCount := 0
 
-	for chunkNum := 0; chunkNum < totalChunks; chunkNum++ {
-		start := chunkNum * chunkSize
-		end := start + chunkSize
-		if end > len(uris) {
-			end = len(uris)
+	created := m.startStage(progress.StagePlaylists, totalChunks)
+	for chunkNum, chunk := range chunks {
+		// Count the previous chunk as handled here, since chunks that are
+		// skipped continue before reaching the end of the loop
+		if chunkNum > 0 {
+			created.add(1)
 		}
-		chunkTracks := uris[start:end]
 
-		// Create playlist name
-		chunkName := fmt.Sprintf("%s-%02d", baseName, chunkNum)
+		if err := ctx.Err(); err != nil {
+			return createdCount, fmt.Errorf("stopped after %d of %d playlists: %w", chunkNum, totalChunks, err)
+		}
 
 		// Check if playlist exists
 		var playlistID spotify.ID
-		if existingID, err := m.FindPlaylistByName(ctx, chunkName); err == nil {
+		if existingID, err := m.FindPlaylistByName(ctx, chunk.Name); err == nil {
 			if !overwrite {
 				continue // Skip existing playlists if not overwriting
 			}
@@ -463,10 +722,11 @@ func (m *Manager) CreateChunkPlaylists(ctx context.Context, sourcePlaylistID spo
 			if err := m.client.ReplacePlaylistTracks(ctx, playlistID); err != nil {
 				continue
 			}
+			// Keep the description in step with the new contents
+			m.client.ChangePlaylistDescription(ctx, playlistID, chunk.Description)
 		} else {
 			// Create new playlist
-			description := fmt.Sprintf("Chunk %d of %d from %s", chunkNum+1, totalChunks, baseName)
-			newID, err := m.CreatePlaylist(ctx, chunkName, description, false)
+			newID, err := m.CreatePlaylist(ctx, chunk.Name, chunk.Description, false)
 			if err != nil {
 				continue
 			}
@@ -474,18 +734,25 @@ func (m *Manager) CreateChunkPlaylists(ctx context.Context, sourcePlaylistID spo
 		}
 
 		// Add tracks to playlist
-		if err := m.replacePlaylistTracks(ctx, playlistID, chunkTracks); err != nil {
+		if err := m.replacePlaylistTracks(ctx, playlistID, trackURIs(chunk.Tracks)); err != nil {
+			var interrupted *InterruptedWriteError
+			if errors.As(err, &interrupted) {
+				return createdCount, fmt.Errorf("playlist '%s' is incomplete: %w", chunk.Name, err)
+			}
 			continue
 		}
 
 		createdCount++
 	}
+	created.add(1)
 
 	return createdCount, nil
 }
 
-// GetPlaylistGenres gets all genres in a playlist with track counts
-func (m *Manager) GetPlaylistGenres(ctx context.Context, playlistID spotify.ID) (map[string]int, error) {
+// GetPlaylistGenres gets all genres in a playlist with track counts. When
+// grouped, micro-genres are counted under their parent genres instead; see
+// ParentGenre.
+func (m *Manager) GetPlaylistGenres(ctx context.Context, playlistID spotify.ID, grouped bool) (map[string]int, error) {
 	tracks, err := m.GetPlaylistTracks(ctx, playlistID)
 	if err != nil {
 		return nil, err
@@ -495,31 +762,22 @@ func (m *Manager) GetPlaylistGenres(ctx context.Context, playlistID spotify.ID)
 		return nil, fmt.Errorf("playlist is empty")
 	}
 
-	// Get track IDs
-	var trackIDs []spotify.ID
-	for _, track := range tracks {
-		trackIDs = append(trackIDs, track.ID)
-	}
-
 	// Get track genres by looking up artists
-	trackGenres, err := m.getTrackGenres(ctx, trackIDs)
+	trackGenres, err := m.getTrackGenres(ctx, tracks)
 	if err != nil {
 		return nil, err
 	}
 
-	// Count genre occurrences
-	genreCounts := make(map[string]int)
-	for _, genres := range trackGenres {
-		for _, genre := range genres {
-			genreCounts[genre]++
-		}
-	}
-
-	return genreCounts, nil
+	return countGenres(trackGenres, grouped), nil
 }
 
-// CreateGenrePlaylist creates a playlist with tracks from a specific genre
-func (m *Manager) CreateGenrePlaylist(ctx context.Context, sourcePlaylistID spotify.ID, name, targetGenre string, overwrite bool) (int, error) {
+// CreateGenrePlaylist creates a playlist with the tracks whose genres pass
+// filter
+func (m *Manager) CreateGenrePlaylist(ctx context.Context, sourcePlaylistID spotify.ID, name string, filter GenreFilter, overwrite bool) (int, error) {
+	if err := filter.Check(); err != nil {
+		return 0, err
+	}
+
 	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
 	if err != nil {
 		return 0, err
@@ -529,68 +787,34 @@ func (m *Manager) CreateGenrePlaylist(ctx context.Context, sourcePlaylistID spot
 		return 0, fmt.Errorf("source playlist is empty")
 	}
 
-	// Get track IDs
-	var trackIDs []spotify.ID
-	for _, track := range tracks {
-		trackIDs = append(trackIDs, track.ID)
-	}
-
-	// Get track genres
-	trackGenres, err := m.getTrackGenres(ctx, trackIDs)
+	// Get track genres by looking up artists
+	trackGenres, err := m.getTrackGenres(ctx, tracks)
 	if err != nil {
 		return 0, err
 	}
 
-	// Filter tracks by genre (case-insensitive partial match)
-	var genreTracks []spotify.URI
-	targetGenreLower := strings.ToLower(targetGenre)
-
-	for _, track := range tracks {
-		if genres, exists := trackGenres[track.ID]; exists {
-			for _, genre := range genres {
-				if strings.Contains(strings.ToLower(genre), targetGenreLower) {
-					genreTracks = append(genreTracks, track.URI)
-					break
-				}
-			}
-		}
+	matched, err := FilterByGenre(tracks, trackGenres, filter)
+	if err != nil {
+		return 0, err
 	}
+	genreTracks := trackURIs(matched)
 
 	if len(genreTracks) == 0 {
-		return 0, fmt.Errorf("no tracks found for genre '%s'", targetGenre)
-	}
-
-	// Check if playlist exists
-	var playlistID spotify.ID
-	if existingID, err := m.FindPlaylistByName(ctx, name); err == nil {
-		if !overwrite {
-			return 0, fmt.Errorf("playlist '%s' already exists", name)
-		}
-		playlistID = existingID
-		// Clear existing playlist
-		if err := m.client.ReplacePlaylistTracks(ctx, playlistID); err != nil {
-			return 0, fmt.Errorf("failed to clear existing playlist: %w", err)
-		}
-	} else {
-		// Create new playlist
-		description := fmt.Sprintf("Tracks with genre: %s", targetGenre)
-		newID, err := m.CreatePlaylist(ctx, name, description, false)
-		if err != nil {
-			return 0, err
-		}
-		playlistID = newID
+		return 0, fmt.Errorf("no tracks found for genre %s", filter.Describe())
 	}
 
-	// Add tracks to playlist
-	if err := m.replacePlaylistTracks(ctx, playlistID, genreTracks); err != nil {
-		return 0, fmt.Errorf("failed to add tracks to playlist: %w", err)
+	description := fmt.Sprintf("Tracks with genre: %s", filter.Describe())
+	if err := m.writeNamedPlaylist(ctx, name, description, genreTracks, overwrite); err != nil {
+		return 0, err
 	}
 
 	return len(genreTracks), nil
 }
 
-// getTrackGenres gets genres for tracks by looking up their artists
-func (m *Manager) getTrackGenres(ctx context.Context, trackIDs []spotify.ID) (map[spotify.ID][]string, error) {
+// getTrackGenres gets genres for tracks by looking up their artists. Artist
+// IDs come from the playlist fetch; only tracks without them (such as
+// entries cached by older versions) are looked up individually.
+func (m *Manager) getTrackGenres(ctx context.Context, tracks []Track) (map[spotify.ID][]string, error) {
 	trackGenres := make(map[spotify.ID][]string)
 	batchSize := 50
 
@@ -598,19 +822,50 @@ func (m *Manager) getTrackGenres(ctx context.Context, trackIDs []spotify.ID) (ma
 	artistIDs := make(map[spotify.ID]bool)
 	trackArtists := make(map[spotify.ID][]spotify.ID)
 
-	// Process tracks in batches
-	for i := 0; i < len(trackIDs); i += batchSize {
-		end := i + batchSize
-		if end > len(trackIDs) {
-			end = len(trackIDs)
+	var toFetch []spotify.ID
+	for _, track := range tracks {
+		// Tracks whose genres are cached need no lookups at all
+		var genres []string
+		if m.cacheGet(bucketGenres, string(track.ID), artistTTL, &genres) {
+			trackGenres[track.ID] = genres
+			continue
+		}
+
+		artists := track.ArtistIDs
+		if len(artists) == 0 {
+			var info cachedTrack
+			if !m.cacheGet(bucketTracks, string(track.ID), trackTTL, &info) {
+				toFetch = append(toFetch, track.ID)
+				continue
+			}
+			artists = info.ArtistIDs
 		}
-		batch := trackIDs[i:end]
 
-		tracks, err := m.client.GetTracks(ctx, batch)
+		trackArtists[track.ID] = artists
+		for _, artistID := range artists {
+			artistIDs[artistID] = true
+		}
+	}
+
+	// Fetch track batches in parallel, then merge them in order
+	trackBatches := splitIDs(toFetch, batchSize)
+	fetchedTracks := make([][]*spotify.FullTrack, len(trackBatches))
+	trackProgress := m.startStage(progress.StageMetadata, len(trackBatches))
+	err := m.forEach(ctx, len(trackBatches), func(ctx context.Context, i int) error {
+		tracks, err := m.client.GetTracks(ctx, trackBatches[i])
 		if err != nil {
-			return nil, fmt.Errorf("failed to get tracks: %w", err)
+			return fmt.Errorf("failed to get tracks: %w", err)
 		}
+		fetchedTracks[i] = tracks
+		trackProgress.add(1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for i, tracks := range fetchedTracks {
+		batch := trackBatches[i]
 		for j, track := range tracks {
 			if track == nil {
 				continue
@@ -622,6 +877,11 @@ func (m *Manager) getTrackGenres(ctx context.Context, trackIDs []spotify.ID) (ma
 				artistIDs[artist.ID] = true
 			}
 			trackArtists[trackID] = artists
+
+			m.cachePut(bucketTracks, string(trackID), cachedTrack{ArtistIDs: artists, AlbumID: track.Album.ID})
+			if track.Album.ID != "" {
+				m.cachePut(bucketAlbums, string(track.Album.ID), cachedAlbum{Name: track.Album.Name, ReleaseDate: track.Album.ReleaseDate})
+			}
 		}
 	}
 
@@ -629,24 +889,36 @@ func (m *Manager) getTrackGenres(ctx context.Context, trackIDs []spotify.ID) (ma
 	artistGenres := make(map[spotify.ID][]string)
 	var artistIDsList []spotify.ID
 	for id := range artistIDs {
-		artistIDsList = append(artistIDsList, id)
-	}
-
-	for i := 0; i < len(artistIDsList); i += batchSize {
-		end := i + batchSize
-		if end > len(artistIDsList) {
-			end = len(artistIDsList)
+		var info cachedArtist
+		if m.cacheGet(bucketArtists, string(id), artistTTL, &info) {
+			artistGenres[id] = info.Genres
+		} else {
+			artistIDsList = append(artistIDsList, id)
 		}
-		batch := artistIDsList[i:end]
+	}
 
-		artists, err := m.client.GetArtists(ctx, batch...)
+	artistBatches := splitIDs(artistIDsList, batchSize)
+	fetchedArtists := make([][]*spotify.FullArtist, len(artistBatches))
+	artistProgress := m.startStage(progress.StageMetadata, len(artistBatches))
+	err = m.forEach(ctx, len(artistBatches), func(ctx context.Context, i int) error {
+		artists, err := m.client.GetArtists(ctx, artistBatches[i]...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get artists: %w", err)
+			return fmt.Errorf("failed to get artists: %w", err)
 		}
+		fetchedArtists[i] = artists
+		artistProgress.add(1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for i, artists := range fetchedArtists {
+		batch := artistBatches[i]
 		for j, artist := range artists {
 			if artist != nil {
 				artistGenres[batch[j]] = artist.Genres
+				m.cachePut(bucketArtists, string(batch[j]), cachedArtist{Name: artist.Name, Genres: artist.Genres})
 			}
 		}
 	}
@@ -666,6 +938,7 @@ func (m *Manager) getTrackGenres(ctx context.Context, trackIDs []spotify.ID) (ma
 			}
 		}
 		trackGenres[trackID] = genres
+		m.cachePut(bucketGenres, string(trackID), genres)
 	}
 
 	return trackGenres, nil
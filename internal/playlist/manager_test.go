@@ -1,10 +1,15 @@
 package playlist
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -332,3 +337,88 @@ func chunkTracks(tracks []Track, size int) [][]Track {
 	}
 	return chunks
 }
+
+func TestWriteNamedPlaylist(t *testing.T) {
+	server, client := newRestoreServer(t)
+	server.tracks.set("p1", testURIs("old", 3))
+	m := NewManager(client)
+	ctx := context.Background()
+	uris := []spotify.URI{"spotify:track:a", "spotify:track:b"}
+
+	if err := m.writeNamedPlaylist(ctx, "road trip", "Fresh tracks", uris, false); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("writeNamedPlaylist(existing) error = %v, want already exists", err)
+	}
+	if got := server.tracks.get("p1"); len(got) != 3 {
+		t.Errorf("tracks without overwrite = %v, want untouched", got)
+	}
+
+	if err := m.writeNamedPlaylist(ctx, "road trip", "Fresh tracks", uris, true); err != nil {
+		t.Fatalf("writeNamedPlaylist(overwrite) error = %v", err)
+	}
+	if got := server.tracks.get("p1"); !reflect.DeepEqual(got, []string{"spotify:track:a", "spotify:track:b"}) {
+		t.Errorf("tracks after overwrite = %v", got)
+	}
+	if server.changed["description"] != "Fresh tracks" {
+		t.Errorf("changed details to %v, want the new description", server.changed)
+	}
+	if server.created != nil {
+		t.Error("overwriting should not create a playlist")
+	}
+
+	if err := m.writeNamedPlaylist(ctx, "Night Drive", "Tracks with genre: synthwave", uris, false); err != nil {
+		t.Fatalf("writeNamedPlaylist(new) error = %v", err)
+	}
+	if server.created["name"] != "Night Drive" || server.created["description"] != "Tracks with genre: synthwave" {
+		t.Errorf("created playlist with %v", server.created)
+	}
+	if got := server.tracks.get("new1"); len(got) != 2 {
+		t.Errorf("new playlist tracks = %v", got)
+	}
+}
+
+func TestPlaylistWithTracks(t *testing.T) {
+	// 150 tracks take two pages; the playlist changes from s1 to s2 while
+	// the second page of the first attempt is fetched
+	items := func(from, to int) string {
+		var out []string
+		for i := from; i < to; i++ {
+			out = append(out, fmt.Sprintf(`{"track":{"id":"t%d","name":"t%d","uri":"spotify:track:t%d"}}`, i, i, i))
+		}
+		return strings.Join(out, ",")
+	}
+	var mu sync.Mutex
+	fetches, snapshot := 0, "s1"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/playlists/p1" && r.URL.Query().Get("fields") == "snapshot_id":
+			fmt.Fprintf(w, `{"snapshot_id":%q}`, snapshot)
+		case r.URL.Path == "/playlists/p1":
+			fetches++
+			fmt.Fprintf(w, `{"id":"p1","name":"Road Trip","snapshot_id":%q,"tracks":{"total":150,"items":[%s]}}`, snapshot, items(0, 100))
+		case r.URL.Path == "/playlists/p1/tracks":
+			if fetches == 1 {
+				snapshot = "s2"
+			}
+			fmt.Fprintf(w, `{"total":150,"items":[%s]}`, items(100, 150))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	m := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+
+	snapshotID, name, tracks, err := m.PlaylistWithTracks(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("PlaylistWithTracks() error = %v", err)
+	}
+	if snapshotID != "s2" || name != "Road Trip" || len(tracks) != 150 || tracks[149].ID != "t149" {
+		t.Errorf("PlaylistWithTracks() = %s, %s, %d tracks", snapshotID, name, len(tracks))
+	}
+	if fetches != 2 {
+		t.Errorf("playlist fetched %d times, want 2 (once more after it changed)", fetches)
+	}
+}
@@ -0,0 +1,87 @@
+package playlist
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// DefaultConcurrency is the number of Spotify requests a manager runs at
+// once unless told otherwise
+const DefaultConcurrency = 4
+
+// SetConcurrency limits how many Spotify requests the manager runs at once.
+// Values below one disable parallel fetching.
+func (m *Manager) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	m.concurrency = n
+}
+
+// forEach calls fn for every index in [0, n) using at most m.concurrency
+// goroutines. The first error cancels the remaining calls and is returned.
+// Rate limiting is left to the client: a throttled request waits out the
+// Retry-After period while holding its slot, so the pool backs off with it.
+func (m *Manager) forEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	if n <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := m.concurrency
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+loop:
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// splitIDs splits ids into consecutive batches of at most size
+func splitIDs(ids []spotify.ID, size int) [][]spotify.ID {
+	var batches [][]spotify.ID
+	for i := 0; i < len(ids); i += size {
+		end := i + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batches = append(batches, ids[i:end])
+	}
+	return batches
+}
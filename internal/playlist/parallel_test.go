@@ -0,0 +1,110 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestForEach_BoundsConcurrency(t *testing.T) {
+	manager := NewManager(&spotify.Client{})
+	manager.SetConcurrency(3)
+
+	var running, peak int32
+	results := make([]int, 20)
+	err := manager.forEach(context.Background(), len(results), func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		results[i] = i * i
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("forEach() error = %v", err)
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+	for i, r := range results {
+		if r != i*i {
+			t.Errorf("results[%d] = %d, want %d", i, r, i*i)
+		}
+	}
+}
+
+func TestForEach_StopsOnError(t *testing.T) {
+	manager := NewManager(&spotify.Client{})
+	manager.SetConcurrency(1)
+
+	boom := errors.New("boom")
+	var calls int32
+	err := manager.forEach(context.Background(), 10, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 2 {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("forEach() error = %v, want %v", err, boom)
+	}
+	if calls >= 10 {
+		t.Errorf("forEach() made %d calls, want it to stop after the error", calls)
+	}
+}
+
+func TestFetchPlaylistTracks_PreservesOrder(t *testing.T) {
+	const total = 250
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var items []string
+		for i := offset; i < offset+limit && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"track":{"id":"t%d","name":"Song %d","uri":"spotify:track:t%d"}}`, i, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"total":%d,"items":[%s]}`, total, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+	manager.SetConcurrency(2)
+
+	tracks, err := manager.fetchPlaylistTracks(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("fetchPlaylistTracks() error = %v", err)
+	}
+	if len(tracks) != total {
+		t.Fatalf("fetchPlaylistTracks() returned %d tracks, want %d", len(tracks), total)
+	}
+	for i, track := range tracks {
+		if want := spotify.ID(fmt.Sprintf("t%d", i)); track.ID != want {
+			t.Fatalf("tracks[%d].ID = %s, want %s", i, track.ID, want)
+		}
+	}
+}
+
+func TestSplitIDs(t *testing.T) {
+	ids := []spotify.ID{"a", "b", "c", "d", "e"}
+	batches := splitIDs(ids, 2)
+	if len(batches) != 3 || len(batches[2]) != 1 || batches[2][0] != "e" {
+		t.Errorf("splitIDs() = %v, want three batches ending in [e]", batches)
+	}
+	if splitIDs(nil, 2) != nil {
+		t.Error("splitIDs(nil) should return nil")
+	}
+}
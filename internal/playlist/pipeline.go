@@ -0,0 +1,501 @@
+package playlist
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// Step is one transformation applied to a playlist's tracks in memory
+type Step struct {
+	Name  string
+	Args  []string
+	apply func(tracks []Track) ([]Track, error)
+}
+
+// String returns the step as it would be written on the command line
+func (s Step) String() string {
+	return strings.TrimSpace(s.Name + " " + strings.Join(s.Args, " "))
+}
+
+// StepNames lists the available pipeline steps with their arguments
+var StepNames = []string{
+	"remove-age DAYS",
+	"remove-artist NAME",
+	"remove-where EXPR",
+	"dedupe",
+	"shuffle [random|spread]",
+	"sort [title|artist|added]",
+	"reverse",
+}
+
+// ParseStep builds a step from its name and arguments, e.g.
+// []string{"remove-age", "90"}
+func ParseStep(words []string) (Step, error) {
+	if len(words) == 0 {
+		return Step{}, fmt.Errorf("empty pipeline step")
+	}
+
+	step := Step{Name: words[0], Args: words[1:]}
+	args := step.Args
+
+	switch step.Name {
+	case "remove-age":
+		if len(args) != 1 {
+			return Step{}, fmt.Errorf("remove-age takes a number of days")
+		}
+		days, err := strconv.Atoi(args[0])
+		if err != nil || days <= 0 {
+			return Step{}, fmt.Errorf("remove-age: days must be a positive number, got %q", args[0])
+		}
+		step.apply = func(tracks []Track) ([]Track, error) {
+			cutoff := time.Now().AddDate(0, 0, -days)
+			return keepTracks(tracks, func(t Track) bool {
+				return t.AddedAt.IsZero() || t.AddedAt.After(cutoff)
+			}), nil
+		}
+
+	case "remove-artist":
+		if len(args) == 0 {
+			return Step{}, fmt.Errorf("remove-artist takes an artist name")
+		}
+		artistLower := strings.ToLower(trimQuotes(strings.Join(args, " ")))
+		step.apply = func(tracks []Track) ([]Track, error) {
+			return keepTracks(tracks, func(t Track) bool {
+				for _, artist := range t.Artists {
+					if strings.Contains(strings.ToLower(artist), artistLower) {
+						return false
+					}
+				}
+				return true
+			}), nil
+		}
+
+	case "remove-where":
+		if len(args) == 0 {
+			return Step{}, fmt.Errorf("remove-where takes a filter expression")
+		}
+		expr, err := ParseExpr(strings.Join(args, " "))
+		if err != nil {
+			return Step{}, fmt.Errorf("remove-where: %w", err)
+		}
+		step.apply = func(tracks []Track) ([]Track, error) {
+			now := time.Now()
+			return keepTracks(tracks, func(t Track) bool { return !expr.root(t, now) }), nil
+		}
+
+	case "dedupe":
+		if len(args) != 0 {
+			return Step{}, fmt.Errorf("dedupe takes no arguments")
+		}
+		step.apply = func(tracks []Track) ([]Track, error) {
+			return dedupeTracks(tracks), nil
+		}
+
+	case "shuffle":
+		mode := "random"
+		if len(args) > 1 {
+			return Step{}, fmt.Errorf("shuffle takes at most one mode")
+		}
+		if len(args) == 1 {
+			mode = args[0]
+		}
+		switch mode {
+		case "random":
+			step.apply = func(tracks []Track) ([]Track, error) {
+				rand.Shuffle(len(tracks), func(i, j int) {
+					tracks[i], tracks[j] = tracks[j], tracks[i]
+				})
+				return tracks, nil
+			}
+		case "spread":
+			step.apply = func(tracks []Track) ([]Track, error) {
+				return spreadTracks(tracks), nil
+			}
+		default:
+			return Step{}, fmt.Errorf("shuffle: unknown mode %q (use 'random' or 'spread')", mode)
+		}
+
+	case "sort":
+		by := "title"
+		if len(args) > 1 {
+			return Step{}, fmt.Errorf("sort takes at most one key")
+		}
+		if len(args) == 1 {
+			by = args[0]
+		}
+		less, err := trackLess(by)
+		if err != nil {
+			return Step{}, err
+		}
+		step.apply = func(tracks []Track) ([]Track, error) {
+			sort.SliceStable(tracks, func(i, j int) bool {
+				return less(tracks[i], tracks[j])
+			})
+			return tracks, nil
+		}
+
+	case "reverse":
+		if len(args) != 0 {
+			return Step{}, fmt.Errorf("reverse takes no arguments")
+		}
+		step.apply = func(tracks []Track) ([]Track, error) {
+			for i, j := 0, len(tracks)-1; i < j; i, j = i+1, j-1 {
+				tracks[i], tracks[j] = tracks[j], tracks[i]
+			}
+			return tracks, nil
+		}
+
+	default:
+		return Step{}, fmt.Errorf("unknown pipeline step %q (available: %s)", step.Name, strings.Join(StepNames, ", "))
+	}
+
+	return step, nil
+}
+
+// trimQuotes removes one pair of matching quotes around s
+func trimQuotes(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// splitPipelineLine splits a pipeline file line or a job's steps into
+// words at whitespace outside quotes. Unlike strings.Fields it keeps a
+// quoted string, spaces and "/" included, inside one word, quotes and all,
+// so remove-where expressions reach ParseExpr intact. A quote only opens
+// at the start of a word or after punctuation, so apostrophes as in
+// "Guns N' Roses" stay literal.
+func splitPipelineLine(line string) ([]string, error) {
+	var words []string
+	var b strings.Builder
+	var quote, prev rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\'') && !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+			quote = r
+		case quote == 0 && unicode.IsSpace(r):
+			if b.Len() > 0 {
+				words = append(words, b.String())
+				b.Reset()
+			}
+			prev = 0
+			continue
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if b.Len() > 0 {
+		words = append(words, b.String())
+	}
+	return words, nil
+}
+
+// closesQuotes reports whether every string quoted in word also ends in it
+func closesQuotes(word string) bool {
+	var quote rune
+	escaped := false
+	for _, r := range word {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		}
+	}
+	return quote == 0
+}
+
+// ParsePipeline parses steps separated by "/" words, e.g.
+// remove-age 90 / dedupe / shuffle spread. The words are command-line
+// arguments, already split by the shell, so a remove-where argument that
+// doesn't close its own quotes means a quoted value was cut in two.
+func ParsePipeline(words []string) ([]Step, error) {
+	return parsePipeline(words, true)
+}
+
+// ParsePipelineText parses a pipeline written as one line of text, such as
+// a job's steps, keeping quoted values whole
+func ParsePipelineText(text string) ([]Step, error) {
+	words, err := splitPipelineLine(text)
+	if err != nil {
+		return nil, err
+	}
+	return parsePipeline(words, false)
+}
+
+func parsePipeline(words []string, args bool) ([]Step, error) {
+	var steps []Step
+	var current []string
+
+	flush := func() error {
+		if args && len(current) > 0 && current[0] == "remove-where" {
+			for _, word := range current[1:] {
+				if !closesQuotes(word) {
+					return fmt.Errorf(`remove-where: a quoted value is split across arguments; quote the whole expression, e.g. remove-where 'artist = "Daft Punk"'`)
+				}
+			}
+		}
+		step, err := ParseStep(current)
+		if err != nil {
+			return err
+		}
+		steps = append(steps, step)
+		current = nil
+		return nil
+	}
+
+	for _, word := range words {
+		if word == "/" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		current = append(current, word)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// ReadPipeline reads a pipeline file with one step per line. Blank lines
+// and lines starting with '#' are ignored.
+func ReadPipeline(r io.Reader) ([]Step, error) {
+	var steps []Step
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words, err := splitPipelineLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		step, err := ParseStep(words)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("pipeline has no steps")
+	}
+	return steps, nil
+}
+
+// StepResult records the track count before and after one step
+type StepResult struct {
+	Step   Step
+	Before int
+	After  int
+}
+
+// PipelineResult describes what a pipeline did to a playlist
+type PipelineResult struct {
+	Steps   []StepResult
+	Before  int
+	After   int
+	Changed bool
+}
+
+// ApplyPipeline runs steps over tracks in order and returns the result
+func ApplyPipeline(tracks []Track, steps []Step) ([]Track, []StepResult, error) {
+	current := append([]Track(nil), tracks...)
+	var results []StepResult
+
+	for _, step := range steps {
+		before := len(current)
+		next, err := step.apply(current)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", step, err)
+		}
+		current = next
+		results = append(results, StepResult{Step: step, Before: before, After: len(current)})
+	}
+
+	return current, results, nil
+}
+
+// RunPipeline downloads a playlist once, applies every step to the track
+// list in memory and writes the result back in a single pass. Nothing is
+// written when dryRun is set or the tracks are unchanged.
+func (m *Manager) RunPipeline(ctx context.Context, playlistID spotify.ID, steps []Step, dryRun bool) (*PipelineResult, error) {
+	tracks, err := m.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	final, stepResults, err := ApplyPipeline(tracks, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	original := trackURIs(tracks)
+	uris := trackURIs(final)
+	result := &PipelineResult{
+		Steps:   stepResults,
+		Before:  len(tracks),
+		After:   len(final),
+		Changed: !equalURIs(original, uris),
+	}
+
+	if dryRun || !result.Changed {
+		return result, nil
+	}
+
+	if err := m.rewritePlaylistTracks(ctx, playlistID, original, uris); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// keepTracks returns the tracks for which keep returns true
+func keepTracks(tracks []Track, keep func(Track) bool) []Track {
+	var kept []Track
+	for _, track := range tracks {
+		if keep(track) {
+			kept = append(kept, track)
+		}
+	}
+	return kept
+}
+
+// dedupeTracks drops repeated tracks, keeping the first occurrence. Tracks
+// are duplicates when they share an ID or an ISRC, which catches the same
+// recording released on several albums.
+func dedupeTracks(tracks []Track) []Track {
+	seenIDs := make(map[string]bool)
+	seenISRCs := make(map[string]bool)
+
+	return keepTracks(tracks, func(t Track) bool {
+		if seenIDs[string(t.ID)] || (t.ISRC != "" && seenISRCs[t.ISRC]) {
+			return false
+		}
+		seenIDs[string(t.ID)] = true
+		if t.ISRC != "" {
+			seenISRCs[t.ISRC] = true
+		}
+		return true
+	})
+}
+
+// spreadTracks shuffles tracks so that songs by the same artist are spread
+// evenly through the playlist instead of clumping together. Each artist's
+// tracks are shuffled and given evenly spaced positions from a random
+// starting offset; the playlist is then ordered by position.
+func spreadTracks(tracks []Track) []Track {
+	groups := make(map[string][]Track)
+	var order []string
+	for _, track := range tracks {
+		key := ""
+		if len(track.Artists) > 0 {
+			key = strings.ToLower(track.Artists[0])
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], track)
+	}
+
+	type placed struct {
+		pos   float64
+		track Track
+	}
+	var all []placed
+	for _, key := range order {
+		group := groups[key]
+		rand.Shuffle(len(group), func(i, j int) {
+			group[i], group[j] = group[j], group[i]
+		})
+
+		step := 1 / float64(len(group))
+		offset := rand.Float64() * step
+		for i, track := range group {
+			all = append(all, placed{pos: offset + float64(i)*step, track: track})
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].pos < all[j].pos
+	})
+
+	spread := make([]Track, len(all))
+	for i, p := range all {
+		spread[i] = p.track
+	}
+	return spread
+}
+
+// trackLess returns the ordering used by the sort step
+func trackLess(by string) (func(a, b Track) bool, error) {
+	firstArtist := func(t Track) string {
+		if len(t.Artists) == 0 {
+			return ""
+		}
+		return strings.ToLower(t.Artists[0])
+	}
+
+	switch by {
+	case "title":
+		return func(a, b Track) bool {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}, nil
+	case "artist":
+		return func(a, b Track) bool {
+			return firstArtist(a) < firstArtist(b)
+		}, nil
+	case "added":
+		return func(a, b Track) bool {
+			return a.AddedAt.Before(b.AddedAt)
+		}, nil
+	default:
+		return nil, fmt.Errorf("sort: unknown key %q (use 'title', 'artist' or 'added')", by)
+	}
+}
+
+func equalURIs(a, b []spotify.URI) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
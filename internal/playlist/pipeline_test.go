@@ -0,0 +1,240 @@
+package playlist
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestParsePipeline(t *testing.T) {
+	steps, err := ParsePipeline(strings.Fields("remove-age 90 / remove-artist Foo Fighters / dedupe / shuffle spread"))
+	if err != nil {
+		t.Fatalf("ParsePipeline() error = %v", err)
+	}
+
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.String())
+	}
+	want := []string{"remove-age 90", "remove-artist Foo Fighters", "dedupe", "shuffle spread"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePipeline() = %v, want %v", got, want)
+	}
+}
+
+func TestParsePipeline_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"remove-age",
+		"remove-age soon",
+		"dedupe / / reverse",
+		"shuffle sideways",
+		"sort by-mood",
+		"remove-where",
+		"remove-where mood = happy",
+		"explode",
+	}
+
+	for _, input := range tests {
+		if _, err := ParsePipeline(strings.Fields(input)); err == nil {
+			t.Errorf("ParsePipeline(%q) expected error", input)
+		}
+	}
+}
+
+func TestParsePipeline_SplitQuotes(t *testing.T) {
+	for _, input := range []string{
+		`remove-where name = "a  b"`,
+		`remove-where artist =~ "AC / DC" / reverse`,
+		`remove-where name = 'old song'`,
+	} {
+		// strings.Fields stands in for the shell splitting unquoted arguments
+		_, err := ParsePipeline(strings.Fields(input))
+		if err == nil || !strings.Contains(err.Error(), "split across arguments") {
+			t.Errorf("ParsePipeline(%q) error = %v, want the split quote rejected", input, err)
+		}
+	}
+
+	for _, input := range []string{
+		`remove-where name = "old" or artist =~ 'a\sb' / reverse`,
+		`remove-where name = "it's" and artist = 'say"hi"'`,
+		`remove-where name = "a\"b"`,
+	} {
+		if _, err := ParsePipeline(strings.Fields(input)); err != nil {
+			t.Errorf("ParsePipeline(%q) error = %v", input, err)
+		}
+	}
+}
+
+func TestSplitPipelineLine(t *testing.T) {
+	tests := map[string][]string{
+		`remove-where artist = "Daft Punk"`:          {"remove-where", "artist", "=", `"Daft Punk"`},
+		`remove-where artist =~ "AC / DC" / reverse`: {"remove-where", "artist", "=~", `"AC / DC"`, "/", "reverse"},
+		`remove-where name='a  b' or name = "x\" y"`: {"remove-where", `name='a  b'`, "or", "name", "=", `"x\" y"`},
+		`remove-artist Guns N' Roses`:                {"remove-artist", "Guns", "N'", "Roses"},
+		`  dedupe   /  shuffle spread `:              {"dedupe", "/", "shuffle", "spread"},
+	}
+	for line, want := range tests {
+		got, err := splitPipelineLine(line)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("splitPipelineLine(%q) = %q, %v; want %q", line, got, err, want)
+		}
+	}
+
+	if _, err := splitPipelineLine(`remove-where name = "open`); err == nil {
+		t.Error("splitPipelineLine() with an unterminated quote expected error")
+	}
+}
+
+func TestParsePipelineText(t *testing.T) {
+	tracks := []Track{
+		{ID: "1", Artists: []string{"Daft Punk"}},
+		{ID: "2", Artists: []string{"AC / DC"}},
+		{ID: "3", Artists: []string{"Guns N' Roses"}},
+		{ID: "4", Artists: []string{"Air"}},
+	}
+
+	steps, err := ParsePipelineText(`remove-where artist = "Daft Punk" / remove-where artist = "AC / DC" / remove-artist "Guns N' Roses"`)
+	if err != nil {
+		t.Fatalf("ParsePipelineText() error = %v", err)
+	}
+	final, _, err := ApplyPipeline(tracks, steps)
+	if err != nil {
+		t.Fatalf("ApplyPipeline() error = %v", err)
+	}
+	if got, want := trackIDs(final), []string{"4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePipelineText() kept %v, want %v", got, want)
+	}
+}
+
+func TestReadPipeline(t *testing.T) {
+	input := `# Weekly clean-up
+remove-age 90
+
+dedupe
+sort artist
+`
+	steps, err := ReadPipeline(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadPipeline() error = %v", err)
+	}
+	if len(steps) != 3 || steps[2].String() != "sort artist" {
+		t.Errorf("ReadPipeline() = %v, want 3 steps ending with sort artist", steps)
+	}
+
+	steps, err = ReadPipeline(strings.NewReader("remove-where artist = \"Daft Punk\" and name != 'One More Time'\n"))
+	if err != nil {
+		t.Fatalf("ReadPipeline() with quoted spaces error = %v", err)
+	}
+	if got := steps[0].String(); got != `remove-where artist = "Daft Punk" and name != 'One More Time'` {
+		t.Errorf("ReadPipeline() step = %s", got)
+	}
+
+	if _, err := ReadPipeline(strings.NewReader("dedupe\nfrobnicate\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ReadPipeline() error = %v, want error naming line 2", err)
+	}
+}
+
+func TestApplyPipeline(t *testing.T) {
+	now := time.Now()
+	tracks := []Track{
+		{ID: "1", Name: "Old", Artists: []string{"A"}, AddedAt: now.AddDate(0, 0, -200)},
+		{ID: "2", Name: "Banana", Artists: []string{"B"}, AddedAt: now.AddDate(0, 0, -5), ISRC: "X1"},
+		{ID: "3", Name: "Apple", Artists: []string{"Foo Fighters"}, AddedAt: now},
+		{ID: "2", Name: "Banana", Artists: []string{"B"}, AddedAt: now},
+		{ID: "4", Name: "Banana (Remaster)", Artists: []string{"B"}, AddedAt: now, ISRC: "X1"},
+		{ID: "5", Name: "Cherry", Artists: []string{"C"}},
+	}
+
+	steps, err := ParsePipeline(strings.Fields("remove-age 90 / remove-artist foo / dedupe / sort title"))
+	if err != nil {
+		t.Fatalf("ParsePipeline() error = %v", err)
+	}
+
+	final, results, err := ApplyPipeline(tracks, steps)
+	if err != nil {
+		t.Fatalf("ApplyPipeline() error = %v", err)
+	}
+
+	if got, want := trackIDs(final), []string{"2", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyPipeline() = %v, want %v", got, want)
+	}
+
+	counts := []int{results[0].After, results[1].After, results[2].After, results[3].After}
+	if want := []int{5, 4, 2, 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("step counts = %v, want %v", counts, want)
+	}
+	if tracks[0].Name != "Old" {
+		t.Error("ApplyPipeline() modified the input slice")
+	}
+
+	steps, err = ParsePipeline(strings.Fields(`remove-where name =~ "^banana" or age > 90d`))
+	if err != nil {
+		t.Fatalf("ParsePipeline() error = %v", err)
+	}
+	final, _, err = ApplyPipeline(tracks, steps)
+	if err != nil {
+		t.Fatalf("ApplyPipeline() error = %v", err)
+	}
+	if got, want := trackIDs(final), []string{"3", "5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("remove-where kept %v, want %v", got, want)
+	}
+}
+
+func TestSpreadTracks(t *testing.T) {
+	var tracks []Track
+	for i := 0; i < 5; i++ {
+		tracks = append(tracks, Track{ID: spotify.ID("a" + string(rune('0'+i))), Artists: []string{"A"}})
+		tracks = append(tracks, Track{ID: spotify.ID("b" + string(rune('0'+i))), Artists: []string{"B"}})
+	}
+
+	for run := 0; run < 20; run++ {
+		spread := spreadTracks(append([]Track(nil), tracks...))
+		if len(spread) != len(tracks) {
+			t.Fatalf("spreadTracks() returned %d tracks, want %d", len(spread), len(tracks))
+		}
+		for i := 1; i < len(spread); i++ {
+			if spread[i].Artists[0] == spread[i-1].Artists[0] {
+				t.Fatalf("spreadTracks() placed two %s tracks next to each other", spread[i].Artists[0])
+			}
+		}
+	}
+}
+
+func TestRunPipeline_SingleWrite(t *testing.T) {
+	fake, client := newFakePlaylist(t)
+	manager := NewManager(client)
+
+	original := testURIs("t", 150)
+	fake.set("p1", original)
+
+	steps, err := ParsePipeline(strings.Fields("reverse / sort title / reverse"))
+	if err != nil {
+		t.Fatalf("ParsePipeline() error = %v", err)
+	}
+
+	result, err := manager.RunPipeline(context.Background(), "p1", steps, true)
+	if err != nil {
+		t.Fatalf("RunPipeline(dry run) error = %v", err)
+	}
+	if !result.Changed || fake.writes != 0 {
+		t.Errorf("dry run: changed = %v, writes = %d; want changed and no writes", result.Changed, fake.writes)
+	}
+
+	if _, err := manager.RunPipeline(context.Background(), "p1", steps, false); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// 150 tracks are one replace and one append, however many steps ran
+	if fake.writes != 2 {
+		t.Errorf("writes = %d, want 2", fake.writes)
+	}
+	got := fake.get("p1")
+	if got[0] != "spotify:track:t149" || got[149] != "spotify:track:t000" {
+		t.Errorf("playlist order = %s ... %s, want reversed", got[0], got[149])
+	}
+}
@@ -0,0 +1,46 @@
+package playlist
+
+import (
+	"sync"
+
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+)
+
+// SetProgress makes the manager report the progress of long operations to
+// r. A nil reporter disables reporting.
+func (m *Manager) SetProgress(r progress.Reporter) {
+	m.progress = r
+}
+
+// stageProgress counts completed work for one stage. Updates are reported
+// while holding the lock so reporters see them in order, even when the
+// work is spread across goroutines.
+type stageProgress struct {
+	reporter progress.Reporter
+	stage    progress.Stage
+	total    int
+
+	mu   sync.Mutex
+	done int
+}
+
+// startStage reports the start of a stage with total units of work
+func (m *Manager) startStage(stage progress.Stage, total int) *stageProgress {
+	p := &stageProgress{reporter: m.progress, stage: stage, total: total}
+	p.add(0)
+	return p
+}
+
+// add records n more completed units of work. Stages without work are not
+// reported.
+func (p *stageProgress) add(n int) {
+	if p.reporter == nil || p.total == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += n
+	p.reporter.Report(progress.Event{Stage: p.stage, Done: p.done, Total: p.total})
+}
@@ -0,0 +1,57 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+	"github.com/zmb3/spotify/v2"
+)
+
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []progress.Event
+}
+
+func (r *recordingReporter) Report(e progress.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func TestFetchPlaylistTracks_ReportsProgress(t *testing.T) {
+	const total = 450
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var items []string
+		for i := offset; i < offset+100 && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"track":{"id":"t%d"}}`, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"total":%d,"items":[%s]}`, total, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	reporter := &recordingReporter{}
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+	manager.SetProgress(reporter)
+
+	if _, err := manager.fetchPlaylistTracks(context.Background(), "p1"); err != nil {
+		t.Fatalf("fetchPlaylistTracks() error = %v", err)
+	}
+
+	if len(reporter.events) != 6 {
+		t.Fatalf("got %d events, want 6: %+v", len(reporter.events), reporter.events)
+	}
+	for i, e := range reporter.events {
+		if e.Stage != progress.StageFetch || e.Done != i || e.Total != 5 {
+			t.Errorf("events[%d] = %+v, want fetch %d/5", i, e, i)
+		}
+	}
+}
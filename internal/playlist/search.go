@@ -0,0 +1,272 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// NamePrefix marks a playlist reference given by name rather than ID,
+// e.g. name:"Road Trip"
+const NamePrefix = "name:"
+
+// PlaylistMatch is a playlist found by name, with how well it matched
+type PlaylistMatch struct {
+	Playlist spotify.SimplePlaylist
+	Score    int
+}
+
+// SearchPlaylists returns the current user's playlists whose names match
+// query, best matches first. With ownedOnly set, followed playlists are
+// left out.
+func (m *Manager) SearchPlaylists(ctx context.Context, query string, ownedOnly bool) ([]PlaylistMatch, error) {
+	playlists, err := m.SelectPlaylists(ctx, ownedOnly, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []PlaylistMatch
+	for _, p := range playlists {
+		if score := FuzzyScore(query, p.Name); score > 0 {
+			matches = append(matches, PlaylistMatch{Playlist: p, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return strings.ToLower(matches[i].Playlist.Name) < strings.ToLower(matches[j].Playlist.Name)
+	})
+	return matches, nil
+}
+
+// ResolvePlaylistName finds the one playlist the user means by name. An
+// exact (case-insensitive) match wins; otherwise the name must be a prefix
+// or substring of a single playlist's name. Commands that rewrite the
+// playlist rely on this, so weaker matches such as typos are never taken
+// on their own: anything else is an AmbiguousPlaylistError listing the
+// candidates.
+func (m *Manager) ResolvePlaylistName(ctx context.Context, name string) (spotify.SimplePlaylist, error) {
+	matches, err := m.SearchPlaylists(ctx, name, false)
+	if err != nil {
+		return spotify.SimplePlaylist{}, err
+	}
+	if len(matches) == 0 {
+		return spotify.SimplePlaylist{}, fmt.Errorf("no playlist matches name %q", name)
+	}
+
+	var exact []PlaylistMatch
+	for _, match := range matches {
+		if match.Score == scoreExact {
+			exact = append(exact, match)
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		return exact[0].Playlist, nil
+	case len(exact) > 1:
+		return spotify.SimplePlaylist{}, &AmbiguousPlaylistError{Name: name, Matches: exact}
+	case len(matches) == 1 && matches[0].Score >= scoreSubstring:
+		return matches[0].Playlist, nil
+	default:
+		return spotify.SimplePlaylist{}, &AmbiguousPlaylistError{Name: name, Matches: matches}
+	}
+}
+
+// ResolvePlaylistRef turns a playlist reference into an ID. References of
+// the form name:"Road Trip" are looked up with ResolvePlaylistName; anything
+// else is taken to be an ID already.
+func (m *Manager) ResolvePlaylistRef(ctx context.Context, ref string) (spotify.ID, error) {
+	if !strings.HasPrefix(ref, NamePrefix) {
+		return spotify.ID(ref), nil
+	}
+
+	name := strings.TrimSpace(strings.TrimPrefix(ref, NamePrefix))
+	if len(name) >= 2 && (name[0] == '"' || name[0] == '\'') && name[len(name)-1] == name[0] {
+		name = name[1 : len(name)-1]
+	}
+	if name == "" {
+		return "", fmt.Errorf("empty playlist name in %q", ref)
+	}
+
+	p, err := m.ResolvePlaylistName(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return p.ID, nil
+}
+
+// SearchTracks searches the Spotify catalogue for tracks
+func (m *Manager) SearchTracks(ctx context.Context, query string, limit int) ([]Track, error) {
+	result, err := m.client.Search(ctx, query, spotify.SearchTypeTrack, spotify.Limit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tracks: %w", err)
+	}
+
+	var tracks []Track
+	if result.Tracks != nil {
+		for _, t := range result.Tracks.Tracks {
+			tracks = append(tracks, newTrack(spotify.PlaylistTrack{Track: t}))
+		}
+	}
+	return tracks, nil
+}
+
+// SearchArtists searches the Spotify catalogue for artists
+func (m *Manager) SearchArtists(ctx context.Context, query string, limit int) ([]spotify.FullArtist, error) {
+	result, err := m.client.Search(ctx, query, spotify.SearchTypeArtist, spotify.Limit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search artists: %w", err)
+	}
+
+	if result.Artists == nil {
+		return nil, nil
+	}
+	return result.Artists.Artists, nil
+}
+
+// Scores returned by FuzzyScore, best first
+const (
+	scoreExact       = 100
+	scorePrefix      = 90
+	scoreSubstring   = 80
+	scoreWords       = 70
+	scoreTypos       = 60
+	scoreSubsequence = 50
+)
+
+// FuzzyScore rates how well name matches query, from 0 (no match) to 100
+// (equal ignoring case and punctuation). In order of preference a match
+// can be a prefix, a substring, contain every query word in any order,
+// contain every query word with at most one typo each, or contain the
+// query's letters in order.
+func FuzzyScore(query, name string) int {
+	q := normalizeName(query)
+	n := normalizeName(name)
+	if q == "" || n == "" {
+		return 0
+	}
+
+	switch {
+	case q == n:
+		return scoreExact
+	case strings.HasPrefix(n, q):
+		return scorePrefix
+	case strings.Contains(n, q):
+		return scoreSubstring
+	}
+
+	queryWords := strings.Fields(q)
+	nameWords := strings.Fields(n)
+	if allWords(queryWords, func(w string) bool { return strings.Contains(n, w) }) {
+		return scoreWords
+	}
+	if allWords(queryWords, func(w string) bool { return closeToAny(w, nameWords) }) {
+		return scoreTypos
+	}
+
+	letters := strings.ReplaceAll(q, " ", "")
+	if span := subsequenceSpan(letters, n); span > 0 {
+		// Tighter matches score higher, down to half the subsequence score
+		return scoreSubsequence/2 + scoreSubsequence/2*len([]rune(letters))/span
+	}
+	return 0
+}
+
+// normalizeName lowercases s and reduces it to letters, digits and single
+// spaces
+func normalizeName(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
+
+func allWords(words []string, ok func(string) bool) bool {
+	for _, w := range words {
+		if !ok(w) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// closeToAny reports whether word is within one edit of a word in words.
+// Words shorter than four letters must match exactly.
+func closeToAny(word string, words []string) bool {
+	for _, w := range words {
+		if w == word || len([]rune(word)) >= 4 && editDistance(word, w) <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance returns the number of single-letter insertions, deletions,
+// substitutions and swaps of adjacent letters that turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// subsequenceSpan returns the length of the shortest stretch of s that
+// contains the letters of q in order, or 0 if there is none
+func subsequenceSpan(q, s string) int {
+	rq, rs := []rune(q), []rune(s)
+	best := 0
+	for start := range rs {
+		if rs[start] != rq[0] {
+			continue
+		}
+		i := 0
+		for j := start; j < len(rs); j++ {
+			if rs[j] == rq[i] {
+				i++
+				if i == len(rq) {
+					if span := j - start + 1; best == 0 || span < best {
+						best = span
+					}
+					break
+				}
+			}
+		}
+	}
+	return best
+}
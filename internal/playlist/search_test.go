@@ -0,0 +1,154 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query string
+		name  string
+		want  int
+	}{
+		{"road trip", "Road Trip", scoreExact},
+		{"Road-Trip!", "road trip", scoreExact},
+		{"road", "Road Trip 2024", scorePrefix},
+		{"trip", "Road Trip 2024", scoreSubstring},
+		{"trip road", "Road Trip", scoreWords},
+		{"raod trip", "Road Trip", scoreTypos},
+		{"rdtrp", "Road Trip", 38},
+		{"jazz", "Road Trip", 0},
+		{"", "Road Trip", 0},
+		{"car", "Road Trip", 0},
+	}
+
+	for _, tt := range tests {
+		if got := FuzzyScore(tt.query, tt.name); got != tt.want {
+			t.Errorf("FuzzyScore(%q, %q) = %d, want %d", tt.query, tt.name, got, tt.want)
+		}
+	}
+}
+
+func newSearchServer(t *testing.T) *Manager {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/me/playlists":
+			w.Write([]byte(`{"items":[
+				{"id":"p1","name":"Road Trip","owner":{"id":"me"}},
+				{"id":"p2","name":"Road Trip 2019","owner":{"id":"me"}},
+				{"id":"p3","name":"Workout","owner":{"id":"me"}},
+				{"id":"p4","name":"Chill","owner":{"id":"me"}},
+				{"id":"p5","name":"chill","owner":{"id":"friend"}}
+			]}`))
+		case "/search":
+			w.Write([]byte(`{"tracks":{"items":[
+				{"id":"t1","name":"Road Trippin'","uri":"spotify:track:t1","artists":[{"id":"a1","name":"Red Hot Chili Peppers"}],"album":{"name":"Californication"}}
+			]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+}
+
+func TestResolvePlaylistName(t *testing.T) {
+	manager := newSearchServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		want          spotify.ID
+		wantAmbiguous int
+		wantErr       string
+	}{
+		{name: "road trip", want: "p1"},
+		{name: "work", want: "p3"},
+		{name: "trip 2019", want: "p2"},
+		{name: "wrkout", wantAmbiguous: 1},
+		{name: "rd trp 19", wantAmbiguous: 1},
+		{name: "road", wantAmbiguous: 2},
+		{name: "Chill", wantAmbiguous: 2},
+		{name: "jazz", wantErr: "no playlist matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := manager.ResolvePlaylistName(ctx, tt.name)
+
+			var ambiguous *AmbiguousPlaylistError
+			switch {
+			case tt.wantAmbiguous > 0:
+				if !errors.As(err, &ambiguous) || len(ambiguous.Matches) != tt.wantAmbiguous {
+					t.Fatalf("ResolvePlaylistName() error = %v, want ambiguity between %d playlists", err, tt.wantAmbiguous)
+				}
+				if !strings.Contains(err.Error(), "use a more specific name or the playlist ID") {
+					t.Errorf("error should say how to disambiguate: %v", err)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolvePlaylistName() error = %v, want %q", err, tt.wantErr)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("ResolvePlaylistName() error = %v", err)
+				}
+				if p.ID != tt.want {
+					t.Errorf("ResolvePlaylistName() = %s, want %s", p.ID, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolvePlaylistRef(t *testing.T) {
+	manager := newSearchServer(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		ref  string
+		want spotify.ID
+	}{
+		{"37i9dQZF1DXcBWIGoYBM5M", "37i9dQZF1DXcBWIGoYBM5M"},
+		{"name:Road Trip", "p1"},
+		{`name:"Road Trip 2019"`, "p2"},
+		{"name: 'workout' ", "p3"},
+	}
+
+	for _, tt := range tests {
+		got, err := manager.ResolvePlaylistRef(ctx, tt.ref)
+		if err != nil {
+			t.Errorf("ResolvePlaylistRef(%q) error = %v", tt.ref, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolvePlaylistRef(%q) = %s, want %s", tt.ref, got, tt.want)
+		}
+	}
+
+	if _, err := manager.ResolvePlaylistRef(ctx, `name:""`); err == nil {
+		t.Error("ResolvePlaylistRef() with an empty name expected error")
+	}
+}
+
+func TestSearchTracks(t *testing.T) {
+	manager := newSearchServer(t)
+
+	tracks, err := manager.SearchTracks(context.Background(), "road trip", 5)
+	if err != nil {
+		t.Fatalf("SearchTracks() error = %v", err)
+	}
+	if len(tracks) != 1 || tracks[0].Name != "Road Trippin'" || tracks[0].Artists[0] != "Red Hot Chili Peppers" || tracks[0].Album != "Californication" {
+		t.Errorf("SearchTracks() = %+v", tracks)
+	}
+}
@@ -0,0 +1,122 @@
+package playlist
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// fakePlaylist is a minimal stand-in for the Spotify playlist tracks API.
+// It keeps the track URIs of each playlist, serves them back page by page
+// and calls onWrite after every successful write.
+type fakePlaylist struct {
+	mu      sync.Mutex
+	tracks  map[string][]string
+	writes  int
+	onWrite func(writes int)
+}
+
+func newFakePlaylist(t *testing.T) (*fakePlaylist, *spotify.Client) {
+	t.Helper()
+	f := &fakePlaylist{tracks: make(map[string][]string)}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/"))
+}
+
+func (f *fakePlaylist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "playlists" || parts[2] != "tracks" {
+		http.NotFound(w, r)
+		return
+	}
+	id := parts[1]
+
+	f.mu.Lock()
+	switch r.Method {
+	case http.MethodGet:
+		f.servePage(w, r, f.tracks[id])
+		f.mu.Unlock()
+		return
+	case http.MethodPut:
+		f.tracks[id] = nil
+		if uris := r.URL.Query().Get("uris"); uris != "" {
+			f.tracks[id] = strings.Split(uris, ",")
+		}
+	case http.MethodPost:
+		var body struct {
+			URIs []string `json:"uris"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.tracks[id] = append(f.tracks[id], body.URIs...)
+	default:
+		f.mu.Unlock()
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+		return
+	}
+	f.writes++
+	writes := f.writes
+	f.mu.Unlock()
+
+	if f.onWrite != nil {
+		f.onWrite(writes)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(`{"snapshot_id":"s"}`))
+}
+
+// servePage writes one page of a playlist's tracks. Callers must hold f.mu.
+func (f *fakePlaylist) servePage(w http.ResponseWriter, r *http.Request, uris []string) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit == 0 {
+		limit = 100
+	}
+
+	var items []string
+	for i := offset; i < offset+limit && i < len(uris); i++ {
+		id := strings.TrimPrefix(uris[i], "spotify:track:")
+		items = append(items, fmt.Sprintf(`{"track":{"id":%q,"name":%q,"uri":%q}}`, id, id, uris[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"total":%d,"items":[%s]}`, len(uris), strings.Join(items, ","))
+}
+
+func (f *fakePlaylist) set(id string, uris []spotify.URI) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tracks[id] = uriStrings(uris)
+}
+
+func (f *fakePlaylist) get(id string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.tracks[id]...)
+}
+
+// trackIDs returns the IDs of tracks in order
+func trackIDs(tracks []Track) []string {
+	var ids []string
+	for _, t := range tracks {
+		ids = append(ids, string(t.ID))
+	}
+	return ids
+}
+
+// testURIs returns n track URIs with the given prefix
+func testURIs(prefix string, n int) []spotify.URI {
+	uris := make([]spotify.URI, n)
+	for i := range uris {
+		uris[i] = spotify.URI(fmt.Sprintf("spotify:track:%s%03d", prefix, i))
+	}
+	return uris
+}
@@ -0,0 +1,176 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// SplitKeys lists the ways PlanSplit can group tracks
+var SplitKeys = []string{"genre", "decade", "artist", "added-by"}
+
+// DefaultSplitNameTemplate names split playlists "Base - group"
+const DefaultSplitNameTemplate = "{base} - {label}"
+
+// SplitOptions controls how a playlist is split into one playlist per group
+type SplitOptions struct {
+	// By is one of SplitKeys
+	By string
+	// MinTracks leaves out groups with fewer tracks; 0 keeps every group
+	MinTracks int
+	// GroupGenres splits by parent genre rather than Spotify's
+	// micro-genres; see ParentGenre
+	GroupGenres bool
+	// NameTemplate names each playlist, with the group as {label}; see
+	// ChunkName. Empty means DefaultSplitNameTemplate.
+	NameTemplate string
+}
+
+// Check reports whether the options describe a valid split
+func (o SplitOptions) Check() error {
+	switch o.By {
+	case "genre", "decade", "artist", "added-by":
+	default:
+		return fmt.Errorf("unknown split %q (available: %s)", o.By, strings.Join(SplitKeys, ", "))
+	}
+	if o.MinTracks < 0 {
+		return fmt.Errorf("minimum tracks must not be negative")
+	}
+	return CheckChunkNameTemplate(o.nameTemplate())
+}
+
+func (o SplitOptions) nameTemplate() string {
+	if o.NameTemplate == "" {
+		return DefaultSplitNameTemplate
+	}
+	return o.NameTemplate
+}
+
+// PlanSplit groups tracks by opts.By and returns a chunk per group, labelled
+// with the group, and the number of groups left out for having fewer than
+// opts.MinTracks tracks. A track with several genres is put in each of
+// them; tracks with no genre, release date or contributor are left out
+// when splitting by that. Decades come in order, other groups largest
+// first. Added-by groups are labelled with user IDs. trackGenres is only
+// used to split by genre.
+func PlanSplit(tracks []Track, opts SplitOptions, trackGenres map[spotify.ID][]string) ([]Chunk, int, error) {
+	if err := opts.Check(); err != nil {
+		return nil, 0, err
+	}
+
+	groups := make(map[string][]Track)
+	labels := make(map[string]string)
+	add := func(label string, track Track) {
+		key := strings.ToLower(label)
+		if _, ok := groups[key]; !ok {
+			labels[key] = label
+		}
+		groups[key] = append(groups[key], track)
+	}
+
+	for _, track := range tracks {
+		switch opts.By {
+		case "genre":
+			genres := trackGenres[track.ID]
+			if opts.GroupGenres {
+				genres = ParentGenres(genres)
+			}
+			for _, genre := range genres {
+				add(genre, track)
+			}
+		case "decade":
+			if year := releaseYear(track.ReleaseDate); year != "" {
+				add(year[:3]+"0s", track)
+			}
+		case "artist":
+			if len(track.Artists) > 0 && track.Artists[0] != "" {
+				add(track.Artists[0], track)
+			}
+		case "added-by":
+			if track.AddedBy != "" {
+				add(track.AddedBy, track)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	small := 0
+	for key, group := range groups {
+		if len(group) < opts.MinTracks {
+			small++
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if opts.By != "decade" && len(groups[keys[i]]) != len(groups[keys[j]]) {
+			return len(groups[keys[i]]) > len(groups[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	chunks := make([]Chunk, len(keys))
+	for i, key := range keys {
+		chunks[i] = Chunk{Label: labels[key], Tracks: groups[key]}
+	}
+	return chunks, small, nil
+}
+
+// PlanSplitPlaylists splits a playlist into one named playlist per group
+// without creating anything, looking up genres and contributor names once
+// for all groups. It also returns the number of groups left out for being
+// smaller than opts.MinTracks.
+func (m *Manager) PlanSplitPlaylists(ctx context.Context, sourcePlaylistID spotify.ID, baseName string, opts SplitOptions) ([]Chunk, int, error) {
+	if err := opts.Check(); err != nil {
+		return nil, 0, err
+	}
+
+	tracks, err := m.GetPlaylistTracks(ctx, sourcePlaylistID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tracks) == 0 {
+		return nil, 0, fmt.Errorf("source playlist is empty")
+	}
+
+	sourceName, err := m.PlaylistName(ctx, sourcePlaylistID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if baseName == "" {
+		baseName = sourceName
+	}
+
+	var trackGenres map[spotify.ID][]string
+	if opts.By == "genre" {
+		if trackGenres, err = m.getTrackGenres(ctx, tracks); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	chunks, small, err := PlanSplit(tracks, opts, trackGenres)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	today := time.Now()
+	for i := range chunks {
+		c := &chunks[i]
+		if opts.By == "added-by" {
+			if user, err := m.client.GetUsersPublicProfile(ctx, spotify.ID(c.Label)); err == nil && user.DisplayName != "" {
+				c.Label = user.DisplayName
+			}
+		}
+		c.Name = ChunkName(opts.nameTemplate(), baseName, i, len(chunks), c.Label, today)
+		c.Description = fmt.Sprintf("'%s' tracks from '%s' (split by %s), created %s",
+			c.Label, sourceName, opts.By, today.Format("2006-01-02"))
+	}
+	if err := checkChunkNames(chunks); err != nil {
+		return nil, 0, err
+	}
+	return chunks, small, nil
+}
@@ -0,0 +1,98 @@
+package playlist
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestPlanSplit_Decade(t *testing.T) {
+	tracks := []Track{
+		{ID: "1", ReleaseDate: "1994-05-01"},
+		{ID: "2", ReleaseDate: "2001"},
+		{ID: "3", ReleaseDate: "1999-12"},
+		{ID: "4", ReleaseDate: ""},
+		{ID: "5", ReleaseDate: "1975"},
+	}
+
+	chunks, small, err := PlanSplit(tracks, SplitOptions{By: "decade"}, nil)
+	if err != nil {
+		t.Fatalf("PlanSplit() error = %v", err)
+	}
+	if small != 0 {
+		t.Errorf("small = %d, want 0", small)
+	}
+	if got, want := chunkLabels(chunks), []string{"1970s", "1990s", "2000s"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+	if got, want := chunkIDs(chunks), []string{"5", "1 3", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tracks = %v, want %v", got, want)
+	}
+}
+
+func TestPlanSplit_GenreMinTracks(t *testing.T) {
+	tracks := []Track{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}
+	trackGenres := map[spotify.ID][]string{
+		"1": {"rock", "indie rock"},
+		"2": {"rock"},
+		"3": {"jazz"},
+	}
+
+	chunks, small, err := PlanSplit(tracks, SplitOptions{By: "genre", MinTracks: 2}, trackGenres)
+	if err != nil {
+		t.Fatalf("PlanSplit() error = %v", err)
+	}
+	if got, want := chunkLabels(chunks), []string{"rock"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+	if small != 2 {
+		t.Errorf("small = %d, want 2 (indie rock and jazz)", small)
+	}
+
+	chunks, _, err = PlanSplit(tracks, SplitOptions{By: "genre", GroupGenres: true}, trackGenres)
+	if err != nil {
+		t.Fatalf("PlanSplit() error = %v", err)
+	}
+	if got, want := chunkIDs(chunks), []string{"1 2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("grouped tracks = %v, want %v (a track counts once per parent)", got, want)
+	}
+}
+
+func TestPlanSplit_ArtistAndAddedBy(t *testing.T) {
+	tracks := []Track{
+		{ID: "1", Artists: []string{"Björk"}, AddedBy: "ana"},
+		{ID: "2", Artists: []string{"Air", "Beck"}, AddedBy: "ben"},
+		{ID: "3", Artists: []string{"björk"}, AddedBy: "ana"},
+		{ID: "4"},
+	}
+
+	chunks, _, err := PlanSplit(tracks, SplitOptions{By: "artist"}, nil)
+	if err != nil {
+		t.Fatalf("PlanSplit() error = %v", err)
+	}
+	if got, want := chunkLabels(chunks), []string{"Björk", "Air"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("artist labels = %v, want %v", got, want)
+	}
+
+	chunks, _, err = PlanSplit(tracks, SplitOptions{By: "added-by"}, nil)
+	if err != nil {
+		t.Fatalf("PlanSplit() error = %v", err)
+	}
+	if got, want := chunkIDs(chunks), []string{"1 3", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("added-by tracks = %v, want %v", got, want)
+	}
+}
+
+func TestSplitOptions_Check(t *testing.T) {
+	for _, opts := range []SplitOptions{
+		{},
+		{By: "mood"},
+		{By: "genre", MinTracks: -1},
+		{By: "genre", NameTemplate: "{base} {genre}"},
+	} {
+		if err := opts.Check(); err == nil {
+			t.Errorf("Check(%+v) = nil, want error", opts)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package playlist
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+	"github.com/zmb3/spotify/v2"
+)
+
+// Count is a named tally, such as the number of tracks by an artist
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Contributor counts the tracks one user added to a playlist
+type Contributor struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Count int    `json:"count"`
+}
+
+// AudioFeatureAverages holds the mean audio features of a playlist's
+// tracks. The 0-1 measures are Spotify's confidence scores.
+type AudioFeatureAverages struct {
+	Tracks           int     `json:"tracks"`
+	Danceability     float64 `json:"danceability"`
+	Energy           float64 `json:"energy"`
+	Valence          float64 `json:"valence"`
+	Acousticness     float64 `json:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness"`
+	Speechiness      float64 `json:"speechiness"`
+	Liveness         float64 `json:"liveness"`
+	Tempo            float64 `json:"tempo"`
+	Loudness         float64 `json:"loudness"`
+}
+
+// Stats summarises a playlist's tracks
+type Stats struct {
+	Tracks          int                   `json:"tracks"`
+	UniqueArtists   int                   `json:"unique_artists"`
+	UniqueAlbums    int                   `json:"unique_albums"`
+	TotalDuration   time.Duration         `json:"-"`
+	DurationSeconds int                   `json:"duration_seconds"`
+	Explicit        int                   `json:"explicit"`
+	ExplicitShare   float64               `json:"explicit_share"`
+	TopArtists      []Count               `json:"top_artists"`
+	TopAlbums       []Count               `json:"top_albums"`
+	ReleaseYears    []Count               `json:"release_years"`
+	AddedByMonth    []Count               `json:"added_by_month"`
+	Contributors    []Contributor         `json:"contributors,omitempty"`
+	Genres          []Count               `json:"genres,omitempty"`
+	AudioFeatures   *AudioFeatureAverages `json:"audio_features,omitempty"`
+
+	// AudioFeaturesError explains why audio features were requested but
+	// are missing; Spotify no longer serves them to every app
+	AudioFeaturesError string `json:"audio_features_error,omitempty"`
+}
+
+// StatsOptions selects the parts of Stats that need extra API calls
+type StatsOptions struct {
+	// Genres looks up every artist's genres
+	Genres bool
+	// GroupGenres counts genres under their parent genres; see ParentGenre
+	GroupGenres bool
+	// AudioFeatures fetches audio features for every track
+	AudioFeatures bool
+}
+
+// AverageDuration returns the mean track length
+func (s *Stats) AverageDuration() time.Duration {
+	if s.Tracks == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Tracks)
+}
+
+// ComputeStats summarises tracks without making any API calls. Artist,
+// album and genre lists are sorted by count, release years and months by
+// date.
+func ComputeStats(tracks []Track) *Stats {
+	stats := &Stats{Tracks: len(tracks)}
+
+	artists := make(map[string]int)
+	albums := make(map[string]int)
+	years := make(map[string]int)
+	months := make(map[string]int)
+	contributors := make(map[string]int)
+
+	for _, track := range tracks {
+		stats.TotalDuration += track.Duration
+		if track.Explicit {
+			stats.Explicit++
+		}
+		for _, artist := range track.Artists {
+			artists[artist]++
+		}
+		if track.Album != "" {
+			albums[track.Album]++
+		}
+		if year := releaseYear(track.ReleaseDate); year != "" {
+			years[year]++
+		}
+		if !track.AddedAt.IsZero() {
+			months[track.AddedAt.Format("2006-01")]++
+		}
+		if track.AddedBy != "" {
+			contributors[track.AddedBy]++
+		}
+	}
+
+	stats.DurationSeconds = int(stats.TotalDuration.Seconds())
+	if stats.Tracks > 0 {
+		stats.ExplicitShare = float64(stats.Explicit) / float64(stats.Tracks)
+	}
+	stats.UniqueArtists = len(artists)
+	stats.UniqueAlbums = len(albums)
+	stats.TopArtists = sortedCounts(artists)
+	stats.TopAlbums = sortedCounts(albums)
+	stats.ReleaseYears = chronologicalCounts(years)
+	stats.AddedByMonth = chronologicalCounts(months)
+
+	// A single contributor is just the owner; only shared playlists get a
+	// breakdown
+	if len(contributors) > 1 {
+		for _, c := range sortedCounts(contributors) {
+			stats.Contributors = append(stats.Contributors, Contributor{ID: c.Name, Count: c.Count})
+		}
+	}
+
+	return stats
+}
+
+// PlaylistStats computes statistics for a playlist, including genres and
+// audio features as selected by opts. Failing to get audio features is
+// reported in the stats rather than as an error.
+func (m *Manager) PlaylistStats(ctx context.Context, playlistID spotify.ID, opts StatsOptions) (*Stats, error) {
+	tracks, err := m.GetPlaylistTracks(ctx, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := ComputeStats(tracks)
+	if len(tracks) == 0 {
+		return stats, nil
+	}
+
+	for i, c := range stats.Contributors {
+		if user, err := m.client.GetUsersPublicProfile(ctx, spotify.ID(c.ID)); err == nil {
+			stats.Contributors[i].Name = user.DisplayName
+		}
+	}
+
+	if opts.Genres {
+		trackGenres, err := m.getTrackGenres(ctx, tracks)
+		if err != nil {
+			return nil, err
+		}
+		stats.Genres = sortedCounts(countGenres(trackGenres, opts.GroupGenres))
+	}
+
+	if opts.AudioFeatures {
+		averages, err := m.getAudioFeatureAverages(ctx, tracks)
+		switch {
+		case ctx.Err() != nil:
+			return nil, err
+		case err != nil:
+			stats.AudioFeaturesError = err.Error()
+		default:
+			stats.AudioFeatures = averages
+		}
+	}
+
+	return stats, nil
+}
+
+// getAudioFeatureAverages fetches audio features for tracks, in parallel
+// batches, and averages them. Tracks Spotify has no features for (local
+// files, podcasts) are left out.
+func (m *Manager) getAudioFeatureAverages(ctx context.Context, tracks []Track) (*AudioFeatureAverages, error) {
+	var features []spotify.AudioFeatures
+	var toFetch []spotify.ID
+	for _, track := range tracks {
+		if track.ID == "" {
+			continue
+		}
+		var cached spotify.AudioFeatures
+		if m.cacheGet(bucketFeatures, string(track.ID), trackTTL, &cached) {
+			features = append(features, cached)
+			continue
+		}
+		toFetch = append(toFetch, track.ID)
+	}
+
+	batches := splitIDs(toFetch, 100)
+	fetched := make([][]*spotify.AudioFeatures, len(batches))
+	stage := m.startStage(progress.StageMetadata, len(batches))
+	err := m.forEach(ctx, len(batches), func(ctx context.Context, i int) error {
+		batch, err := m.client.GetAudioFeatures(ctx, batches[i]...)
+		if err != nil {
+			return fmt.Errorf("failed to get audio features: %w", err)
+		}
+		fetched[i] = batch
+		stage.add(1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, batch := range fetched {
+		for _, f := range batch {
+			if f == nil {
+				continue
+			}
+			m.cachePut(bucketFeatures, string(f.ID), f)
+			features = append(features, *f)
+		}
+	}
+
+	averages := &AudioFeatureAverages{Tracks: len(features)}
+	if len(features) == 0 {
+		return averages, nil
+	}
+	for _, f := range features {
+		averages.Danceability += float64(f.Danceability)
+		averages.Energy += float64(f.Energy)
+		averages.Valence += float64(f.Valence)
+		averages.Acousticness += float64(f.Acousticness)
+		averages.Instrumentalness += float64(f.Instrumentalness)
+		averages.Speechiness += float64(f.Speechiness)
+		averages.Liveness += float64(f.Liveness)
+		averages.Tempo += float64(f.Tempo)
+		averages.Loudness += float64(f.Loudness)
+	}
+	n := float64(len(features))
+	averages.Danceability /= n
+	averages.Energy /= n
+	averages.Valence /= n
+	averages.Acousticness /= n
+	averages.Instrumentalness /= n
+	averages.Speechiness /= n
+	averages.Liveness /= n
+	averages.Tempo /= n
+	averages.Loudness /= n
+
+	return averages, nil
+}
+
+// releaseYear returns the year of a Spotify release date, which may be
+// given as YYYY, YYYY-MM or YYYY-MM-DD
+func releaseYear(date string) string {
+	if len(date) < 4 {
+		return ""
+	}
+	if _, err := strconv.Atoi(date[:4]); err != nil {
+		return ""
+	}
+	return date[:4]
+}
+
+// sortedCounts returns the tallies in m, largest first, ties by name
+func sortedCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for name, count := range m {
+		counts = append(counts, Count{Name: name, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
+
+// chronologicalCounts returns the tallies in m ordered by name, for keys
+// such as years and months that sort as dates
+func chronologicalCounts(m map[string]int) []Count {
+	counts := make([]Count, 0, len(m))
+	for name, count := range m {
+		counts = append(counts, Count{Name: name, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
@@ -0,0 +1,130 @@
+package playlist
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestComputeStats(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	tracks := []Track{
+		{ID: "1", Artists: []string{"A", "B"}, Album: "X", ReleaseDate: "1999-05-01", Duration: 3 * time.Minute, Explicit: true, AddedAt: day("2024-01-10"), AddedBy: "alice"},
+		{ID: "2", Artists: []string{"A"}, Album: "X", ReleaseDate: "1999", Duration: 4 * time.Minute, AddedAt: day("2024-01-20"), AddedBy: "bob"},
+		{ID: "3", Artists: []string{"C"}, Album: "Y", ReleaseDate: "2021-03", Duration: 5 * time.Minute, AddedAt: day("2024-03-01"), AddedBy: "alice"},
+		{ID: "4", Artists: []string{"C"}, Album: "Z", Duration: 2 * time.Minute, AddedBy: "alice"},
+	}
+
+	stats := ComputeStats(tracks)
+
+	if stats.Tracks != 4 || stats.UniqueArtists != 3 || stats.UniqueAlbums != 3 {
+		t.Errorf("counts = %d tracks, %d artists, %d albums; want 4, 3, 3", stats.Tracks, stats.UniqueArtists, stats.UniqueAlbums)
+	}
+	if stats.TotalDuration != 14*time.Minute || stats.DurationSeconds != 840 || stats.AverageDuration() != 210*time.Second {
+		t.Errorf("duration = %v (%ds), average %v", stats.TotalDuration, stats.DurationSeconds, stats.AverageDuration())
+	}
+	if stats.Explicit != 1 || stats.ExplicitShare != 0.25 {
+		t.Errorf("explicit = %d (%.2f), want 1 (0.25)", stats.Explicit, stats.ExplicitShare)
+	}
+
+	wantArtists := []Count{{"A", 2}, {"C", 2}, {"B", 1}}
+	if !reflect.DeepEqual(stats.TopArtists, wantArtists) {
+		t.Errorf("TopArtists = %v, want %v", stats.TopArtists, wantArtists)
+	}
+	if stats.TopAlbums[0] != (Count{"X", 2}) {
+		t.Errorf("TopAlbums[0] = %v, want X with 2", stats.TopAlbums[0])
+	}
+
+	wantYears := []Count{{"1999", 2}, {"2021", 1}}
+	if !reflect.DeepEqual(stats.ReleaseYears, wantYears) {
+		t.Errorf("ReleaseYears = %v, want %v", stats.ReleaseYears, wantYears)
+	}
+	wantMonths := []Count{{"2024-01", 2}, {"2024-03", 1}}
+	if !reflect.DeepEqual(stats.AddedByMonth, wantMonths) {
+		t.Errorf("AddedByMonth = %v, want %v", stats.AddedByMonth, wantMonths)
+	}
+
+	wantContributors := []Contributor{{ID: "alice", Count: 3}, {ID: "bob", Count: 1}}
+	if !reflect.DeepEqual(stats.Contributors, wantContributors) {
+		t.Errorf("Contributors = %v, want %v", stats.Contributors, wantContributors)
+	}
+}
+
+func TestComputeStats_SingleContributor(t *testing.T) {
+	stats := ComputeStats([]Track{{ID: "1", AddedBy: "me"}, {ID: "2", AddedBy: "me"}})
+	if stats.Contributors != nil {
+		t.Errorf("Contributors = %v, want none for a single-owner playlist", stats.Contributors)
+	}
+}
+
+func TestPlaylistStats(t *testing.T) {
+	featuresStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/tracks"):
+			w.Write([]byte(`{"total":2,"items":[
+				{"added_by":{"id":"alice"},"track":{"id":"t1","uri":"spotify:track:t1","artists":[{"id":"a1","name":"A"}]}},
+				{"added_by":{"id":"bob"},"track":{"id":"t2","uri":"spotify:track:t2","artists":[{"id":"a1","name":"A"}]}}
+			]}`))
+		case strings.HasPrefix(r.URL.Path, "/users/"):
+			w.Write([]byte(`{"id":"` + strings.TrimPrefix(r.URL.Path, "/users/") + `","display_name":"Display"}`))
+		case r.URL.Path == "/artists":
+			w.Write([]byte(`{"artists":[{"id":"a1","name":"A","genres":["indie rock","shoegaze"]}]}`))
+		case r.URL.Path == "/audio-features":
+			if featuresStatus != http.StatusOK {
+				w.WriteHeader(featuresStatus)
+				w.Write([]byte(`{"error":{"status":403,"message":"Forbidden"}}`))
+				return
+			}
+			w.Write([]byte(`{"audio_features":[
+				{"id":"t1","danceability":0.5,"energy":0.2,"tempo":100,"loudness":-6},
+				{"id":"t2","danceability":0.7,"energy":0.4,"tempo":140,"loudness":-8},
+				null
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	manager := NewManager(spotify.New(server.Client(), spotify.WithBaseURL(server.URL+"/")))
+
+	stats, err := manager.PlaylistStats(context.Background(), "p1", StatsOptions{Genres: true, AudioFeatures: true})
+	if err != nil {
+		t.Fatalf("PlaylistStats() error = %v", err)
+	}
+
+	if len(stats.Contributors) != 2 || stats.Contributors[0].Name != "Display" {
+		t.Errorf("Contributors = %+v, want two named contributors", stats.Contributors)
+	}
+	wantGenres := []Count{{"indie rock", 2}, {"shoegaze", 2}}
+	if !reflect.DeepEqual(stats.Genres, wantGenres) {
+		t.Errorf("Genres = %v, want %v", stats.Genres, wantGenres)
+	}
+
+	f := stats.AudioFeatures
+	if f == nil || f.Tracks != 2 || math.Abs(f.Danceability-0.6) > 1e-6 || f.Tempo != 120 || f.Loudness != -7 {
+		t.Errorf("AudioFeatures = %+v, want averages of two tracks", f)
+	}
+
+	// Apps without access to audio features still get the rest
+	featuresStatus = http.StatusForbidden
+	stats, err = manager.PlaylistStats(context.Background(), "p1", StatsOptions{AudioFeatures: true})
+	if err != nil {
+		t.Fatalf("PlaylistStats() error = %v", err)
+	}
+	if stats.AudioFeatures != nil || stats.AudioFeaturesError == "" || stats.Tracks != 2 {
+		t.Errorf("stats = %+v, want tracks counted and an audio features error", stats)
+	}
+}
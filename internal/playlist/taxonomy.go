@@ -0,0 +1,105 @@
+package playlist
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+// OtherGenre is the parent of genres the taxonomy doesn't know
+const OtherGenre = "Other"
+
+// genreTaxonomy maps Spotify's micro-genres to broad parent genres. A genre
+// belongs to the first parent with a keyword among its words, so the order
+// settles mixed names: "rap metal" is Metal, "pop punk" Punk and "blues
+// rock" Blues. Keywords match whole words, so compounds such as
+// "metalcore" or "synthpop" are listed as they are spelled.
+var genreTaxonomy = []struct {
+	Parent   string
+	Keywords []string
+}{
+	{"Metal", []string{"metal", "metalcore", "djent", "grindcore", "deathcore", "doom", "sludge", "thrash"}},
+	{"Hip Hop", []string{"hip hop", "rap", "trap", "drill", "grime", "boom bap"}},
+	{"Punk", []string{"punk", "hardcore", "emo", "screamo"}},
+	{"Electronic", []string{"electronic", "edm", "house", "techno", "trance", "dubstep", "drum and bass", "dnb", "electro", "electropop", "ambient", "downtempo", "idm", "breakbeat", "synthwave", "trip hop"}},
+	{"Reggae", []string{"reggae", "dancehall", "ska", "dub"}},
+	{"Latin", []string{"latin", "reggaeton", "salsa", "bachata", "cumbia", "samba", "bossa nova", "mpb", "tango", "flamenco", "banda", "corrido", "mariachi"}},
+	{"Jazz", []string{"jazz", "bebop", "big band"}},
+	{"Blues", []string{"blues"}},
+	{"R&B/Soul", []string{"r&b", "soul", "funk", "motown", "new jack swing"}},
+	{"Classical", []string{"classical", "orchestra", "orchestral", "baroque", "opera", "symphonic", "choral", "early music"}},
+	{"Country", []string{"country", "bluegrass", "americana", "honky tonk"}},
+	{"Folk", []string{"folk", "singer-songwriter", "acoustic"}},
+	{"Rock", []string{"rock", "rockabilly", "grunge", "shoegaze", "new wave"}},
+	{"Pop", []string{"pop", "synthpop", "britpop", "hyperpop", "boy band", "girl group"}},
+	{"Indie", []string{"indie"}},
+}
+
+// ParentGenre returns the broad genre a Spotify genre belongs to, such as
+// "Metal" for "melodic death metal", or OtherGenre
+func ParentGenre(genre string) string {
+	words := genreWords(genre)
+	for _, entry := range genreTaxonomy {
+		for _, keyword := range entry.Keywords {
+			if containsWords(words, genreWords(keyword)) {
+				return entry.Parent
+			}
+		}
+	}
+	return OtherGenre
+}
+
+// genreWords splits a genre into lower-case words at spaces and hyphens
+func genreWords(genre string) []string {
+	return strings.FieldsFunc(strings.ToLower(genre), func(r rune) bool {
+		return r == ' ' || r == '-'
+	})
+}
+
+// containsWords reports whether seq appears as consecutive words in words
+func containsWords(words, seq []string) bool {
+	for i := 0; i+len(seq) <= len(words); i++ {
+		match := true
+		for j := range seq {
+			if words[i+j] != seq[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// ParentGenres returns the distinct parent genres of genres, sorted
+func ParentGenres(genres []string) []string {
+	seen := make(map[string]bool)
+	var parents []string
+	for _, genre := range genres {
+		if parent := ParentGenre(genre); !seen[parent] {
+			seen[parent] = true
+			parents = append(parents, parent)
+		}
+	}
+	sort.Strings(parents)
+	return parents
+}
+
+// countGenres counts the tracks with each genre, or with each parent genre
+// when grouped, so a track with several metal micro-genres counts once
+// towards Metal
+func countGenres(trackGenres map[spotify.ID][]string, grouped bool) map[string]int {
+	counts := make(map[string]int)
+	for _, genres := range trackGenres {
+		if grouped {
+			genres = ParentGenres(genres)
+		}
+		for _, genre := range genres {
+			counts[genre]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,61 @@
+package playlist
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify/v2"
+)
+
+func TestParentGenre(t *testing.T) {
+	tests := map[string]string{
+		"melodic death metal": "Metal",
+		"rap metal":           "Metal",
+		"pop rap":             "Hip Hop",
+		"pop punk":            "Punk",
+		"dubstep":             "Electronic",
+		"roots reggae":        "Reggae",
+		"blues rock":          "Blues",
+		"neo soul":            "R&B/Soul",
+		"k-pop":               "Pop",
+		"indie rock":          "Rock",
+		"bedroom indie":       "Indie",
+		"Classic Rock":        "Rock",
+		"vaporwave":           OtherGenre,
+		"metalcore":           "Metal",
+		"hip-hop":             "Hip Hop",
+		"drum-and-bass":       "Electronic",
+		"singer-songwriter":   "Folk",
+		"rockabilly":          "Rock",
+		"dublin indie":        "Indie",
+		"dubai indie":         "Indie",
+		"alaska indie":        "Indie",
+		"nebraska indie":      "Indie",
+		"ska punk":            "Punk",
+		"dub":                 "Reggae",
+	}
+	for genre, want := range tests {
+		if got := ParentGenre(genre); got != want {
+			t.Errorf("ParentGenre(%q) = %q, want %q", genre, got, want)
+		}
+	}
+}
+
+func TestCountGenres(t *testing.T) {
+	trackGenres := map[spotify.ID][]string{
+		"1": {"death metal", "thrash metal"},
+		"2": {"death metal", "grunge"},
+		"3": {"vaporwave"},
+	}
+
+	if got, want := countGenres(trackGenres, false), map[string]int{
+		"death metal": 2, "thrash metal": 1, "grunge": 1, "vaporwave": 1,
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("countGenres(ungrouped) = %v, want %v", got, want)
+	}
+	if got, want := countGenres(trackGenres, true), map[string]int{
+		"Metal": 2, "Rock": 1, OtherGenre: 1,
+	}; !reflect.DeepEqual(got, want) {
+		t.Errorf("countGenres(grouped) = %v, want %v", got, want)
+	}
+}
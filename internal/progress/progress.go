@@ -0,0 +1,248 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stage identifies a phase of a long-running operation
+type Stage string
+
+const (
+	StageFetch     Stage = "fetch"
+	StageMetadata  Stage = "metadata"
+	StageWrite     Stage = "write"
+	StagePlaylists Stage = "playlists"
+)
+
+// Label returns a human-readable description of the stage
+func (s Stage) Label() string {
+	switch s {
+	case StageFetch:
+		return "📥 Fetching tracks"
+	case StageMetadata:
+		return "🎵 Fetching metadata"
+	case StageWrite:
+		return "✍️  Writing tracks"
+	case StagePlaylists:
+		return "📝 Creating playlists"
+	default:
+		return string(s)
+	}
+}
+
+// Event reports how far a stage has got. Done counts units of work (pages,
+// batches, tracks or playlists) out of Total.
+type Event struct {
+	Stage Stage
+	Done  int
+	Total int
+}
+
+// Reporter receives progress events. Implementations must be safe for
+// concurrent use, since work is often done by several goroutines at once.
+type Reporter interface {
+	Report(Event)
+}
+
+// New returns the reporter suited to w and the output format: JSON events
+// for "json", a redrawn progress bar when w is a terminal, and periodic log
+// lines otherwise.
+func New(w io.Writer, format string) Reporter {
+	if format == "json" {
+		return NewJSON(w)
+	}
+	if isTerminal(w) {
+		return NewBar(w)
+	}
+	return NewLog(w, 5*time.Second)
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// tracker remembers when each stage started so an ETA can be estimated
+type tracker struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	starts map[Stage]time.Time
+}
+
+func newTracker() tracker {
+	return tracker{now: time.Now, starts: make(map[Stage]time.Time)}
+}
+
+// update records e and returns the time elapsed in its stage and the
+// estimated time remaining (zero when unknown). Callers must hold t.mu.
+func (t *tracker) update(e Event) (elapsed, eta time.Duration) {
+	now := t.now()
+	start, ok := t.starts[e.Stage]
+	if !ok || e.Done == 0 {
+		start = now
+		t.starts[e.Stage] = start
+	}
+	if e.Total > 0 && e.Done >= e.Total {
+		delete(t.starts, e.Stage)
+	}
+
+	elapsed = now.Sub(start)
+	if e.Done > 0 && e.Done < e.Total {
+		eta = time.Duration(float64(elapsed) / float64(e.Done) * float64(e.Total-e.Done))
+	}
+	return elapsed, eta
+}
+
+// Bar renders a progress bar that is redrawn in place
+type Bar struct {
+	tracker
+	w     io.Writer
+	width int
+	last  Stage
+	open  bool
+}
+
+// NewBar returns a reporter that draws a progress bar on w
+func NewBar(w io.Writer) *Bar {
+	return &Bar{tracker: newTracker(), w: w, width: 30}
+}
+
+// Report redraws the bar for e. Single-step stages are not shown.
+func (b *Bar) Report(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, eta := b.update(e)
+	if e.Total <= 1 {
+		return
+	}
+
+	// A new stage starts on its own line
+	if b.open && b.last != e.Stage {
+		fmt.Fprintln(b.w)
+	}
+	b.last = e.Stage
+
+	filled := b.width * e.Done / e.Total
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", b.width-filled)
+	line := fmt.Sprintf("\r%s [%s] %d/%d %3d%%", e.Stage.Label(), bar, e.Done, e.Total, 100*e.Done/e.Total)
+	if eta > 0 {
+		line += " ETA " + FormatDuration(eta)
+	}
+	// Pad to clear leftovers from a longer previous line
+	fmt.Fprintf(b.w, "%-*s", len(line)+4, line)
+
+	b.open = e.Done < e.Total
+	if !b.open {
+		fmt.Fprintln(b.w)
+	}
+}
+
+// Log prints a progress line at most once per interval, plus one when a
+// stage finishes. It suits log files and CI output.
+type Log struct {
+	tracker
+	w        io.Writer
+	interval time.Duration
+	printed  map[Stage]time.Time
+}
+
+// NewLog returns a reporter that writes periodic log lines to w
+func NewLog(w io.Writer, interval time.Duration) *Log {
+	return &Log{tracker: newTracker(), w: w, interval: interval, printed: make(map[Stage]time.Time)}
+}
+
+// Report logs e if the interval has passed or the stage is complete.
+// Single-step stages are not shown.
+func (l *Log) Report(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elapsed, eta := l.update(e)
+	if e.Total <= 1 {
+		return
+	}
+
+	done := e.Done >= e.Total
+	now := l.now()
+	if !done {
+		// Quick stages only get their completion line
+		last, seen := l.printed[e.Stage]
+		if seen && now.Sub(last) < l.interval || !seen && elapsed < l.interval {
+			return
+		}
+	}
+	l.printed[e.Stage] = now
+
+	line := fmt.Sprintf("%s: %d/%d (%d%%)", e.Stage.Label(), e.Done, e.Total, 100*e.Done/e.Total)
+	switch {
+	case done:
+		line += " in " + FormatDuration(elapsed)
+		delete(l.printed, e.Stage)
+	case eta > 0:
+		line += ", ETA " + FormatDuration(eta)
+	}
+	fmt.Fprintln(l.w, line)
+}
+
+// JSON writes one JSON object per event, for consumption by other tools
+type JSON struct {
+	tracker
+	enc *json.Encoder
+}
+
+type jsonEvent struct {
+	Event          string  `json:"event"`
+	Stage          Stage   `json:"stage"`
+	Done           int     `json:"done"`
+	Total          int     `json:"total"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	ETASeconds     float64 `json:"eta_seconds,omitempty"`
+}
+
+// NewJSON returns a reporter that writes JSON lines to w
+func NewJSON(w io.Writer) *JSON {
+	return &JSON{tracker: newTracker(), enc: json.NewEncoder(w)}
+}
+
+// Report writes e as a JSON line
+func (j *JSON) Report(e Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	elapsed, eta := j.update(e)
+	j.enc.Encode(jsonEvent{
+		Event:          "progress",
+		Stage:          e.Stage,
+		Done:           e.Done,
+		Total:          e.Total,
+		ElapsedSeconds: elapsed.Round(time.Millisecond).Seconds(),
+		ETASeconds:     eta.Round(time.Second).Seconds(),
+	})
+}
+
+// FormatDuration formats d as m:ss, or h:mm:ss for long durations
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
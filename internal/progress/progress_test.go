@@ -0,0 +1,117 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a now func and a function that advances it
+func fakeClock() (func() time.Time, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestLog_Report(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLog(&buf, 5*time.Second)
+	now, advance := fakeClock()
+	l.now = now
+
+	l.Report(Event{Stage: StageFetch, Done: 0, Total: 10})
+	advance(time.Second)
+	l.Report(Event{Stage: StageFetch, Done: 1, Total: 10})
+	if buf.Len() != 0 {
+		t.Fatalf("Log printed before the interval elapsed: %q", buf.String())
+	}
+
+	advance(5 * time.Second)
+	l.Report(Event{Stage: StageFetch, Done: 5, Total: 10})
+	advance(time.Second)
+	l.Report(Event{Stage: StageFetch, Done: 6, Total: 10})
+	advance(4 * time.Second)
+	l.Report(Event{Stage: StageFetch, Done: 10, Total: 10})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Log printed %d lines, want 2: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "5/10 (50%), ETA 0:06") {
+		t.Errorf("first line = %q, want progress with ETA", lines[0])
+	}
+	if !strings.Contains(lines[1], "10/10 (100%) in 0:11") {
+		t.Errorf("last line = %q, want completion with elapsed time", lines[1])
+	}
+}
+
+func TestLog_SkipsSingleStepStages(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLog(&buf, time.Second)
+	l.Report(Event{Stage: StageFetch, Done: 0, Total: 1})
+	l.Report(Event{Stage: StageFetch, Done: 1, Total: 1})
+	if buf.Len() != 0 {
+		t.Errorf("Log printed %q for a single-step stage", buf.String())
+	}
+}
+
+func TestJSON_Report(t *testing.T) {
+	var buf bytes.Buffer
+	j := NewJSON(&buf)
+	now, advance := fakeClock()
+	j.now = now
+
+	j.Report(Event{Stage: StageWrite, Done: 0, Total: 300})
+	advance(2 * time.Second)
+	j.Report(Event{Stage: StageWrite, Done: 100, Total: 300})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("JSON wrote %d lines, want 2", len(lines))
+	}
+
+	var got jsonEvent
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[1], err)
+	}
+	want := jsonEvent{Event: "progress", Stage: StageWrite, Done: 100, Total: 300, ElapsedSeconds: 2, ETASeconds: 4}
+	if got != want {
+		t.Errorf("event = %+v, want %+v", got, want)
+	}
+}
+
+func TestBar_Report(t *testing.T) {
+	var buf bytes.Buffer
+	b := NewBar(&buf)
+	b.width = 10
+
+	b.Report(Event{Stage: StageFetch, Done: 5, Total: 10})
+	if !strings.Contains(buf.String(), "[█████░░░░░] 5/10  50%") {
+		t.Errorf("bar = %q, want half-filled bar", buf.String())
+	}
+	if strings.HasSuffix(buf.String(), "\n") {
+		t.Error("unfinished bar should not end the line")
+	}
+
+	b.Report(Event{Stage: StageFetch, Done: 10, Total: 10})
+	if !strings.HasSuffix(buf.String(), "\n") {
+		t.Error("finished bar should end the line")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{42 * time.Second, "0:42"},
+		{3*time.Minute + 5*time.Second, "3:05"},
+		{2*time.Hour + 3*time.Minute, "2:03:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.in); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
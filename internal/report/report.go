@@ -0,0 +1,131 @@
+// Package report renders playlist statistics as a self-contained HTML page.
+// Charts are inline SVG and styles are embedded, so the file can be opened
+// or shared without any other assets or network access.
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+	"github.com/petabloc/spotify-shuffle/internal/progress"
+)
+
+// Report is the data rendered into the HTML page
+type Report struct {
+	PlaylistID   string
+	PlaylistName string
+	Generated    time.Time
+	Stats        *playlist.Stats
+	// Top is how many artists and genres are shown before the rest are
+	// left out (artists) or merged into "Other" (genres)
+	Top int
+}
+
+// page is what the template sees
+type page struct {
+	Report
+	TotalDuration   string
+	AverageDuration string
+	ExplicitPercent string
+	GenreChart      template.HTML
+	YearChart       template.HTML
+	ArtistChart     template.HTML
+	AddedChart      template.HTML
+	HasGenres       bool
+}
+
+// Render writes r as a standalone HTML document to w
+func Render(w io.Writer, r Report) error {
+	if r.Stats == nil {
+		return fmt.Errorf("no statistics to render")
+	}
+	if r.Top < 1 {
+		r.Top = 10
+	}
+
+	stats := r.Stats
+	p := page{
+		Report:          r,
+		TotalDuration:   progress.FormatDuration(stats.TotalDuration),
+		AverageDuration: progress.FormatDuration(stats.AverageDuration()),
+		ExplicitPercent: fmt.Sprintf("%.0f%%", 100*stats.ExplicitShare),
+		GenreChart:      PieChart(stats.Genres, r.Top),
+		YearChart:       Histogram(stats.ReleaseYears),
+		ArtistChart:     BarChart(stats.TopArtists, r.Top),
+		AddedChart:      LineChart(ContinuousMonths(stats.AddedByMonth)),
+		HasGenres:       stats.Genres != nil,
+	}
+
+	if err := pageTemplate.Execute(w, p); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+var pageTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.PlaylistName}} – Playlist Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; background: #f6f6f6; color: #191414; }
+header { background: #191414; color: #fff; padding: 24px 32px; }
+header h1 { margin: 0 0 4px; font-size: 28px; }
+header p { margin: 0; color: #b3b3b3; font-size: 14px; }
+main { max-width: 960px; margin: 0 auto; padding: 24px 32px; }
+.summary { display: grid; grid-template-columns: repeat(auto-fit, minmax(140px, 1fr)); gap: 12px; margin-bottom: 24px; }
+.summary div { background: #fff; border-radius: 8px; padding: 12px 16px; }
+.summary b { display: block; font-size: 22px; }
+.summary span { color: #6a6a6a; font-size: 13px; }
+section { background: #fff; border-radius: 8px; padding: 16px 24px; margin-bottom: 24px; }
+section h2 { margin-top: 0; font-size: 18px; }
+section .note { color: #6a6a6a; font-size: 13px; margin-top: -8px; }
+.chart { width: 100%; height: auto; font-size: 12px; fill: #191414; }
+.chart .axis { stroke: #b3b3b3; stroke-width: 1; }
+.chart .tick { font-size: 10px; fill: #6a6a6a; }
+.empty { color: #6a6a6a; font-style: italic; }
+footer { text-align: center; color: #6a6a6a; font-size: 12px; padding-bottom: 24px; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.PlaylistName}}</h1>
+<p>{{.PlaylistID}} · generated {{.Generated.Format "2006-01-02 15:04"}}</p>
+</header>
+<main>
+<div class="summary">
+<div><b>{{.Stats.Tracks}}</b><span>tracks</span></div>
+<div><b>{{.TotalDuration}}</b><span>total duration</span></div>
+<div><b>{{.AverageDuration}}</b><span>average track</span></div>
+<div><b>{{.Stats.UniqueArtists}}</b><span>artists</span></div>
+<div><b>{{.Stats.UniqueAlbums}}</b><span>albums</span></div>
+<div><b>{{.ExplicitPercent}}</b><span>explicit</span></div>
+</div>
+{{if .HasGenres}}
+<section>
+<h2>Genres</h2>
+<p class="note">Share of genre tags across all tracks; a track counts once for each of its artists' genres.</p>
+{{.GenreChart}}
+</section>
+{{end}}
+<section>
+<h2>Release years</h2>
+{{.YearChart}}
+</section>
+<section>
+<h2>Top artists</h2>
+{{.ArtistChart}}
+</section>
+<section>
+<h2>Tracks added per month</h2>
+{{.AddedChart}}
+</section>
+</main>
+<footer>Generated by spotify-shuffle</footer>
+</body>
+</html>
+`))
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+func TestRender(t *testing.T) {
+	stats := &playlist.Stats{
+		Tracks:        3,
+		UniqueArtists: 2,
+		UniqueAlbums:  2,
+		TotalDuration: 10 * time.Minute,
+		ExplicitShare: 1.0 / 3,
+		TopArtists:    []playlist.Count{{Name: "Queen", Count: 2}, {Name: "ABBA", Count: 1}},
+		ReleaseYears:  []playlist.Count{{Name: "1975", Count: 2}, {Name: "1976", Count: 1}},
+		AddedByMonth:  []playlist.Count{{Name: "2024-01", Count: 2}, {Name: "2024-03", Count: 1}},
+		Genres:        []playlist.Count{{Name: "rock", Count: 2}, {Name: "pop", Count: 1}},
+	}
+
+	var buf bytes.Buffer
+	err := Render(&buf, Report{
+		PlaylistID:   "p1",
+		PlaylistName: "Rock & Roll <Classics>",
+		Generated:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Stats:        stats,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"Rock &amp; Roll &lt;Classics&gt;",
+		"2024-05-01 12:00",
+		"10:00",
+		"33%",
+		"Genres",
+		"Queen",
+		"2024-02",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	if got := strings.Count(out, "<svg"); got != 4 {
+		t.Errorf("report has %d charts, want 4", got)
+	}
+
+	// Self-contained: nothing is loaded from elsewhere
+	external := regexp.MustCompile(`(?i)<(script|link|img)\b|\bsrc=|href=`)
+	if loc := external.FindString(out); loc != "" {
+		t.Errorf("report references external content: %q", loc)
+	}
+}
+
+func TestRenderWithoutGenres(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, Report{PlaylistName: "Empty", Stats: &playlist.Stats{}}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "<h2>Genres</h2>") {
+		t.Error("genre section should be left out when genres were not looked up")
+	}
+}
+
+func TestRenderWithoutStats(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, Report{}); err == nil {
+		t.Error("Render() without stats should fail")
+	}
+}
@@ -0,0 +1,252 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+// palette colours chart series; slices beyond it reuse colours in order
+var palette = []string{
+	"#1db954", "#2e77d0", "#e8115b", "#f59b23", "#8c67ab",
+	"#27856a", "#e91429", "#509bf5", "#af2896", "#777777",
+}
+
+// otherColour is used for the slice that gathers the remaining genres
+const otherColour = "#b3b3b3"
+
+// PieChart draws counts as a pie with a legend. Counts after the first
+// limit are merged into an "Other" slice.
+func PieChart(counts []playlist.Count, limit int) template.HTML {
+	slices := topWithOther(counts, limit)
+	total := 0
+	for _, c := range slices {
+		total += c.Count
+	}
+	if total == 0 {
+		return emptyChart()
+	}
+
+	const size, radius = 220.0, 100.0
+	cx, cy := size/2, size/2
+	legendX := size + 20
+
+	var b strings.Builder
+	height := math.Max(size, float64(len(slices))*22+10)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" class="chart" role="img">`, legendX+260, height)
+
+	angle := -math.Pi / 2
+	for i, c := range slices {
+		colour := sliceColour(i, c.Name)
+		share := float64(c.Count) / float64(total)
+
+		if len(slices) == 1 {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s"><title>%s</title></circle>`, cx, cy, radius, colour, label(c, total))
+		} else {
+			end := angle + share*2*math.Pi
+			large := 0
+			if share > 0.5 {
+				large = 1
+			}
+			fmt.Fprintf(&b, `<path d="M%.1f,%.1f L%.1f,%.1f A%.1f,%.1f 0 %d,1 %.1f,%.1f Z" fill="%s" stroke="#fff" stroke-width="1"><title>%s</title></path>`,
+				cx, cy, cx+radius*math.Cos(angle), cy+radius*math.Sin(angle), radius, radius, large,
+				cx+radius*math.Cos(end), cy+radius*math.Sin(end), colour, label(c, total))
+			angle = end
+		}
+
+		y := 20 + float64(i)*22
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.0f" width="14" height="14" fill="%s"/>`, legendX, y-11, colour)
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.0f">%s</text>`, legendX+22, y, label(c, total))
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// BarChart draws counts as horizontal bars, largest first, showing at
+// most limit bars
+func BarChart(counts []playlist.Count, limit int) template.HTML {
+	if len(counts) == 0 {
+		return emptyChart()
+	}
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+
+	const labelWidth, barWidth, rowHeight = 200.0, 360.0, 24.0
+	largest := 0
+	for _, c := range counts {
+		largest = max(largest, c.Count)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" class="chart" role="img">`,
+		labelWidth+barWidth+50, float64(len(counts))*rowHeight+10)
+
+	for i, c := range counts {
+		y := 5 + float64(i)*rowHeight
+		width := barWidth * float64(c.Count) / float64(largest)
+		fmt.Fprintf(&b, `<text x="%.0f" y="%.0f" text-anchor="end">%s</text>`, labelWidth-8, y+15, html.EscapeString(truncate(c.Name, 28)))
+		fmt.Fprintf(&b, `<rect x="%.0f" y="%.0f" width="%.1f" height="%.0f" fill="%s"><title>%s</title></rect>`,
+			labelWidth, y+2, width, rowHeight-6, palette[1], html.EscapeString(fmt.Sprintf("%s: %d", c.Name, c.Count)))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.0f">%d</text>`, labelWidth+width+6, y+15, c.Count)
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// Histogram draws counts as vertical bars in the order given, such as
+// release years
+func Histogram(counts []playlist.Count) template.HTML {
+	if len(counts) == 0 {
+		return emptyChart()
+	}
+
+	const width, height, bottom, left = 640.0, 220.0, 40.0, 36.0
+	plotWidth := width - left - 10
+	slot := plotWidth / float64(len(counts))
+	largest := 0
+	for _, c := range counts {
+		largest = max(largest, c.Count)
+	}
+
+	// Label every nth bar so labels don't overlap
+	every := int(math.Ceil(float64(len(counts)) * 40 / plotWidth))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" class="chart" role="img">`, width, height)
+	axis(&b, left, height-bottom, width-10, 10, largest)
+
+	for i, c := range counts {
+		h := (height - bottom - 10) * float64(c.Count) / float64(largest)
+		x := left + float64(i)*slot
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s</title></rect>`,
+			x+slot*0.1, height-bottom-h, slot*0.8, h, palette[0], html.EscapeString(fmt.Sprintf("%s: %d", c.Name, c.Count)))
+		if i%every == 0 {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle" class="tick">%s</text>`, x+slot/2, height-bottom+16, html.EscapeString(c.Name))
+		}
+	}
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// LineChart draws counts as a line through evenly spaced points, such as
+// tracks added per month
+func LineChart(counts []playlist.Count) template.HTML {
+	if len(counts) == 0 {
+		return emptyChart()
+	}
+
+	const width, height, bottom, left = 640.0, 220.0, 40.0, 36.0
+	plotWidth := width - left - 20
+	largest := 0
+	for _, c := range counts {
+		largest = max(largest, c.Count)
+	}
+	largest = max(largest, 1)
+
+	step := 0.0
+	if len(counts) > 1 {
+		step = plotWidth / float64(len(counts)-1)
+	}
+	every := int(math.Ceil(float64(len(counts)) * 60 / plotWidth))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" class="chart" role="img">`, width, height)
+	axis(&b, left, height-bottom, width-10, 10, largest)
+
+	var points []string
+	for i, c := range counts {
+		x := left + 10 + float64(i)*step
+		y := height - bottom - (height-bottom-10)*float64(c.Count)/float64(largest)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s</title></circle>`, x, y, palette[2], html.EscapeString(fmt.Sprintf("%s: %d", c.Name, c.Count)))
+		if i%every == 0 {
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle" class="tick">%s</text>`, x, height-bottom+16, html.EscapeString(c.Name))
+		}
+	}
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(points, " "), palette[2])
+
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// ContinuousMonths fills the gaps between the first and last month in
+// counts (keyed "2006-01") with zero counts, so a timeline is evenly spaced
+func ContinuousMonths(counts []playlist.Count) []playlist.Count {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	byMonth := make(map[string]int)
+	for _, c := range counts {
+		byMonth[c.Name] = c.Count
+	}
+
+	first, err1 := time.Parse("2006-01", counts[0].Name)
+	last, err2 := time.Parse("2006-01", counts[len(counts)-1].Name)
+	if err1 != nil || err2 != nil {
+		return counts
+	}
+
+	var filled []playlist.Count
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		filled = append(filled, playlist.Count{Name: key, Count: byMonth[key]})
+	}
+	return filled
+}
+
+// axis draws the x and y axes with a label for the largest value
+func axis(b *strings.Builder, x0, y0, x1, yTop float64, largest int) {
+	fmt.Fprintf(b, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" class="axis"/>`, x0, y0, x1, y0)
+	fmt.Fprintf(b, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" class="axis"/>`, x0, y0, x0, yTop)
+	fmt.Fprintf(b, `<text x="%.0f" y="%.0f" text-anchor="end" class="tick">%d</text>`, x0-4, yTop+4, largest)
+	fmt.Fprintf(b, `<text x="%.0f" y="%.0f" text-anchor="end" class="tick">0</text>`, x0-4, y0)
+}
+
+// topWithOther returns the first limit counts plus one "Other" count that
+// sums the rest
+func topWithOther(counts []playlist.Count, limit int) []playlist.Count {
+	if len(counts) <= limit {
+		return counts
+	}
+	top := append([]playlist.Count(nil), counts[:limit]...)
+	other := playlist.Count{Name: "Other"}
+	for _, c := range counts[limit:] {
+		other.Count += c.Count
+	}
+	return append(top, other)
+}
+
+func sliceColour(i int, name string) string {
+	if name == "Other" {
+		return otherColour
+	}
+	return palette[i%len(palette)]
+}
+
+// label formats a count with its share of total, escaped for SVG text
+func label(c playlist.Count, total int) string {
+	return html.EscapeString(fmt.Sprintf("%s (%d, %.0f%%)", truncate(c.Name, 30), c.Count, 100*float64(c.Count)/float64(total)))
+}
+
+func emptyChart() template.HTML {
+	return template.HTML(`<p class="empty">No data</p>`)
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
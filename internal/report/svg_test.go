@@ -0,0 +1,98 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petabloc/spotify-shuffle/internal/playlist"
+)
+
+func TestPieChart(t *testing.T) {
+	counts := []playlist.Count{{Name: "rock", Count: 5}, {Name: "pop", Count: 3}, {Name: "jazz", Count: 1}, {Name: "folk", Count: 1}}
+
+	svg := string(PieChart(counts, 2))
+
+	if got := strings.Count(svg, "<path"); got != 3 {
+		t.Errorf("got %d slices, want 3 (two genres and Other)", got)
+	}
+	for _, want := range []string{"rock (5, 50%)", "pop (3, 30%)", "Other (2, 20%)"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("pie chart missing label %q", want)
+		}
+	}
+	if !strings.Contains(svg, otherColour) {
+		t.Error("Other slice should use the grey colour")
+	}
+}
+
+func TestPieChartSingleSlice(t *testing.T) {
+	svg := string(PieChart([]playlist.Count{{Name: "rock", Count: 4}}, 10))
+
+	// A full-circle arc has identical endpoints and would draw nothing
+	if !strings.Contains(svg, "<circle") || strings.Contains(svg, "<path") {
+		t.Errorf("single slice should be drawn as a circle: %s", svg)
+	}
+}
+
+func TestChartsEscapeLabels(t *testing.T) {
+	counts := []playlist.Count{{Name: `<script>alert("x")</script>`, Count: 2}}
+
+	charts := map[string]string{
+		"pie":       string(PieChart(counts, 10)),
+		"bar":       string(BarChart(counts, 10)),
+		"histogram": string(Histogram(counts)),
+		"line":      string(LineChart(counts)),
+	}
+	for name, svg := range charts {
+		if strings.Contains(svg, "<script>") {
+			t.Errorf("%s chart did not escape its labels", name)
+		}
+	}
+}
+
+func TestChartsWithoutData(t *testing.T) {
+	for name, svg := range map[string]string{
+		"pie":       string(PieChart(nil, 10)),
+		"bar":       string(BarChart(nil, 10)),
+		"histogram": string(Histogram(nil)),
+		"line":      string(LineChart(nil)),
+	} {
+		if strings.Contains(svg, "<svg") || !strings.Contains(svg, "No data") {
+			t.Errorf("%s chart without data = %q", name, svg)
+		}
+	}
+}
+
+func TestBarChartLimit(t *testing.T) {
+	counts := []playlist.Count{{Name: "A", Count: 3}, {Name: "B", Count: 2}, {Name: "C", Count: 1}}
+
+	svg := string(BarChart(counts, 2))
+
+	if got := strings.Count(svg, "<rect"); got != 2 {
+		t.Errorf("got %d bars, want 2", got)
+	}
+	if strings.Contains(svg, ">C<") {
+		t.Error("bar chart should leave out counts past the limit")
+	}
+}
+
+func TestContinuousMonths(t *testing.T) {
+	counts := []playlist.Count{{Name: "2023-11", Count: 2}, {Name: "2024-02", Count: 1}}
+
+	got := ContinuousMonths(counts)
+
+	want := []playlist.Count{{Name: "2023-11", Count: 2}, {Name: "2023-12", Count: 0}, {Name: "2024-01", Count: 0}, {Name: "2024-02", Count: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ContinuousMonths() = %v, want %v", got, want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("Sigur Rós", 20); got != "Sigur Rós" {
+		t.Errorf("short string changed: %q", got)
+	}
+	if got := truncate("Sigur Rós", 7); got != "Sigur …" {
+		t.Errorf("truncate() = %q, want %q", got, "Sigur …")
+	}
+}